@@ -1,48 +1,534 @@
 package main
 
 import (
+	"flag"
+	"fmt"
 	"log"
 	"os"
+	"slices"
+	"strings"
+	"system_prototype_for_biathlon_competitions/internal/clock"
+	"system_prototype_for_biathlon_competitions/internal/config"
 	"system_prototype_for_biathlon_competitions/internal/entities"
+	"system_prototype_for_biathlon_competitions/internal/messages"
 	"system_prototype_for_biathlon_competitions/internal/services"
+	"system_prototype_for_biathlon_competitions/internal/storage"
+	"time"
 )
 
+const (
+	defaultConfigPath  = "internal/config/config.json"
+	defaultEventsPath  = "sunny_5_skiers/events"
+	defaultStoragePath = "races"
+)
+
+// pathList представляет собой список путей, заданных повторением флага.
+type pathList []string
+
+func (l *pathList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *pathList) Set(path string) error {
+	if slices.Contains(*l, path) {
+		return fmt.Errorf("path %q is given more than once", path)
+	}
+	*l = append(*l, path)
+	return nil
+}
+
 func main() {
-	configFile, err := os.Open("internal/config/config.json")
+	args := os.Args[1:]
+	command := "run"
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		command, args = args[0], args[1:]
+	}
+
+	var err error
+	switch command {
+	case "run":
+		err = runRace(args)
+	case "validate":
+		err = validateFiles(args)
+	case "config":
+		err = configCommand(args)
+	case "races":
+		err = listRaces(args)
+	case "export":
+		err = exportRace(args)
+	case "publish":
+		err = publishRace(args)
+	case "protest":
+		err = fileProtest(args)
+	case "decide":
+		err = decideProtest(args)
+	case "official":
+		err = makeOfficial(args)
+	default:
+		err = fmt.Errorf("unknown command %q", command)
+	}
 	if err != nil {
-		log.Printf("failed to open config file: %v\n", err)
-		return
+		log.Printf("%v\n", err)
+		os.Exit(1)
 	}
-	defer configFile.Close()
+}
+
+// openEventSources открывает файлы событий paths (по умолчанию - файл событий по умолчанию) и добавляет их
+// в files: один файл как файл событий, несколько как источники для объединения. Возвращаемая функция
+// закрывает открытые файлы; при ошибке уже открытые файлы закрываются сразу.
+func openEventSources(files *entities.Files, paths pathList) (func(), error) {
+	if len(paths) == 0 {
+		paths = pathList{defaultEventsPath}
+	}
+
+	var opened []*os.File
+	closeAll := func() {
+		for _, file := range opened {
+			file.Close()
+		}
+	}
+	for _, path := range paths {
+		file := os.Stdin
+		if path != "-" {
+			var err error
+			file, err = os.Open(path)
+			if err != nil {
+				closeAll()
+				return nil, fmt.Errorf("failed to open events file: %w", err)
+			}
+			opened = append(opened, file)
+		}
+		files.EventSources = append(files.EventSources, &entities.EventSource{Name: path, File: file})
+	}
+	if len(files.EventSources) == 1 {
+		files.EventsFile, files.EventSources = files.EventSources[0].File, nil
+	}
+
+	return closeAll, nil
+}
 
-	eventsFile, err := os.Open("sunny_5_skiers/events")
+// runRace обрабатывает события гонки, формирует файл 'report' и сохраняет гонку в хранилище.
+func runRace(args []string) error {
+	flags := flag.NewFlagSet("run", flag.ExitOnError)
+	configPath := flags.String("config", defaultConfigPath, "path to config file")
+	var eventsPaths pathList
+	flags.Var(&eventsPaths, "events", "path to events file, - for standard input; repeat to merge events of several timing devices")
+	live := flags.Bool("live", false, "process events as they arrive, tracking deadlines by the wall clock")
+	tick := flags.Duration("tick", 100*time.Millisecond, "wall clock polling period in live mode")
+	lenient := flags.Bool("lenient", false, "skip invalid event lines and report all of them instead of stopping at the first")
+	athletesPath := flags.String("athletes", "", "path to athletes registry file (CSV or JSON), e.g. examples/athletes.csv")
+	correctionsPath := flags.String("corrections", "", "path to jury corrections file (JSON)")
+	storagePath := flags.String("storage", defaultStoragePath, "path to races storage directory")
+	raceID := flags.String("id", time.Now().Format("2006-01-02_15-04-05"), "race identifier")
+	locale := flags.String("locale", messages.DefaultLocale, "language of the output log and report headers: en or ru")
+	flags.Parse(args)
+
+	catalog, err := messages.New(*locale)
 	if err != nil {
-		log.Printf("failed to open events file: %v\n", err)
-		return
+		return err
 	}
-	defer eventsFile.Close()
 
-	files := &entities.Files{
-		ConfigFile: configFile,
-		EventsFile: eventsFile,
+	configFile, err := os.Open(*configPath)
+	if err != nil {
+		return fmt.Errorf("failed to open config file: %w", err)
+	}
+	defer configFile.Close()
+
+	files := &entities.Files{ConfigFile: configFile}
+	closeEvents, err := openEventSources(files, eventsPaths)
+	if err != nil {
+		return err
+	}
+	defer closeEvents()
+	if *athletesPath != "" {
+		athletesFile, err := os.Open(*athletesPath)
+		if err != nil {
+			return fmt.Errorf("failed to open athletes file: %w", err)
+		}
+		defer athletesFile.Close()
+		files.AthletesFile = athletesFile
+	}
+	if *correctionsPath != "" {
+		correctionsFile, err := os.Open(*correctionsPath)
+		if err != nil {
+			return fmt.Errorf("failed to open corrections file: %w", err)
+		}
+		defer correctionsFile.Close()
+		files.CorrectionsFile = correctionsFile
 	}
 	service := services.NewParseService(files)
+	service.SetMessages(catalog)
+	service.SetLenient(*lenient)
 
 	config, err := service.ParseConfig()
 	if err != nil {
-		log.Printf("failed to parse config file: %v", err)
-		return
+		return fmt.Errorf("failed to parse config file: %w", err)
+	}
+
+	athletes, err := service.ParseAthletes()
+	if err != nil {
+		return fmt.Errorf("failed to parse athletes file: %w", err)
+	}
+
+	corrections, err := service.ParseCorrections()
+	if err != nil {
+		return fmt.Errorf("failed to parse corrections file: %w", err)
+	}
+
+	var statistics storage.StatisticRepository
+	if *live {
+		statistics, err = service.ParseEventsLive(config, clock.WallClock{}, *tick)
+	} else {
+		statistics, err = service.ParseEvents(config)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to parse events file: %w", err)
+	}
+	if lineErrors := service.LineErrors(); len(lineErrors) > 0 {
+		log.Printf("%d invalid event lines skipped:\n", len(lineErrors))
+		for _, lineErr := range lineErrors {
+			log.Println(lineErr)
+		}
+	}
+	if duplicates := service.DuplicateEvents(); len(duplicates) > 0 {
+		log.Printf("%d duplicate events dropped while merging sources:\n", len(duplicates))
+		for _, duplicate := range duplicates {
+			log.Println(services.FormatDuplicateEvent(duplicate, catalog))
+		}
+	}
+	if diagnostics := service.OrderDiagnostics(); len(diagnostics) > 0 {
+		log.Printf("%d events out of time order:\n", len(diagnostics))
+		for _, diagnostic := range diagnostics {
+			log.Println(services.FormatOrderDiagnostic(diagnostic, catalog))
+		}
+	}
+
+	if err := services.ValidateAthletes(athletes, statistics); err != nil {
+		return fmt.Errorf("failed to validate athletes registry: %w", err)
+	}
+
+	violations, err := services.CheckPenaltyLaps(statistics, config)
+	if err != nil {
+		return fmt.Errorf("failed to check penalty laps: %w", err)
+	}
+	services.ApplyPenaltyViolations(statistics, violations)
+	if err := services.ApplyCorrections(statistics, corrections); err != nil {
+		return fmt.Errorf("failed to apply corrections: %w", err)
+	}
+	if err := services.MakeJuryReport(violations, catalog); err != nil {
+		return fmt.Errorf("failed to make jury report: %w", err)
 	}
 
-	statistics, err := service.ParseEvents(config)
+	raceService, err := openRaceService(*storagePath)
 	if err != nil {
-		log.Printf("failed to parse events file: %v", err)
-		return
+		return err
+	}
+	race := &entities.Race{
+		ID:          *raceID,
+		Config:      config,
+		Athletes:    athletes,
+		Corrections: corrections,
+		Events:      service.Events(),
+
+		EventOrigins: service.EventOrigins(),
+	}
+	if err := raceService.SaveRace(race, statistics); err != nil {
+		return err
 	}
 
-	reportService := services.NewReportService(statistics, config)
+	reportService := services.NewRaceReportService(race)
+	reportService.Messages = catalog
 	if err := reportService.MakeResultingTable(); err != nil {
-		log.Printf("failed to make resulting table: %v", err)
-		return
+		return fmt.Errorf("failed to make resulting table: %w", err)
+	}
+	if err := reportService.MakeGroupedTables(); err != nil {
+		return fmt.Errorf("failed to make grouped tables: %w", err)
+	}
+	if err := reportService.MakePenaltyLoopsReport(); err != nil {
+		return fmt.Errorf("failed to make penalty loops report: %w", err)
+	}
+	if err := reportService.MakeAnalysisReport(); err != nil {
+		return fmt.Errorf("failed to make analysis report: %w", err)
+	}
+	if err := reportService.MakeShootingReport(); err != nil {
+		return fmt.Errorf("failed to make shooting report: %w", err)
+	}
+
+	return nil
+}
+
+// validateFiles проверяет конфигурационный файл и файл событий без формирования отчёта
+// и выводит найденные ошибки и предупреждения. При наличии ошибок возвращает ошибку.
+func validateFiles(args []string) error {
+	flags := flag.NewFlagSet("validate", flag.ExitOnError)
+	configPath := flags.String("config", defaultConfigPath, "path to config file")
+	var eventsPaths pathList
+	flags.Var(&eventsPaths, "events", "path to events file, - for standard input; repeat to check merged events of several timing devices")
+	locale := flags.String("locale", messages.DefaultLocale, "language of the validation report: en or ru")
+	flags.Parse(args)
+
+	catalog, err := messages.New(*locale)
+	if err != nil {
+		return err
 	}
+
+	configFile, err := os.Open(*configPath)
+	if err != nil {
+		return fmt.Errorf("failed to open config file: %w", err)
+	}
+	defer configFile.Close()
+
+	files := &entities.Files{ConfigFile: configFile}
+	closeEvents, err := openEventSources(files, eventsPaths)
+	if err != nil {
+		return err
+	}
+	defer closeEvents()
+
+	service := services.NewParseService(files)
+	service.SetMessages(catalog)
+	report, err := service.Validate()
+	if err != nil {
+		return fmt.Errorf("failed to validate files: %w", err)
+	}
+	for _, line := range report.Lines() {
+		fmt.Println(line)
+	}
+	if report.HasErrors() {
+		return fmt.Errorf("validation failed: %d errors", report.Count(services.SeverityError))
+	}
+
+	return nil
+}
+
+// configCommand выполняет подкоманду работы с конфигурационным файлом.
+func configCommand(args []string) error {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		return fmt.Errorf("config subcommand is required: convert")
+	}
+	switch args[0] {
+	case "convert":
+		return convertConfig(args[1:])
+	default:
+		return fmt.Errorf("unknown config subcommand %q", args[0])
+	}
+}
+
+// convertConfig переводит конфигурационный файл в другой формат (JSON, YAML или TOML)
+// после той же проверки, что и при запуске гонки.
+func convertConfig(args []string) error {
+	flags := flag.NewFlagSet("config convert", flag.ExitOnError)
+	configPath := flags.String("config", defaultConfigPath, "path to config file (JSON, YAML or TOML)")
+	format := flags.String("to", "", "output format: json, yaml or toml (by the output file extension by default)")
+	outputPath := flags.String("o", "", "path to output file (stdout by default)")
+	flags.Parse(args)
+
+	if *format == "" {
+		*format = config.FormatByName(*outputPath)
+	}
+	if !slices.Contains(config.Formats, *format) {
+		return fmt.Errorf("output format is required: %s", strings.Join(config.Formats, ", "))
+	}
+
+	configFile, err := os.Open(*configPath)
+	if err != nil {
+		return fmt.Errorf("failed to open config file: %w", err)
+	}
+	defer configFile.Close()
+
+	cfg, err := services.NewParseService(&entities.Files{ConfigFile: configFile}).ParseConfig()
+	if err != nil {
+		return fmt.Errorf("failed to parse config file: %w", err)
+	}
+
+	output := os.Stdout
+	if *outputPath != "" {
+		output, err = os.Create(*outputPath)
+		if err != nil {
+			return fmt.Errorf("failed to create output file: %w", err)
+		}
+		defer output.Close()
+	}
+
+	return config.Encode(output, cfg, *format)
+}
+
+// listRaces выводит список сохранённых гонок.
+func listRaces(args []string) error {
+	flags := flag.NewFlagSet("races", flag.ExitOnError)
+	storagePath := flags.String("storage", defaultStoragePath, "path to races storage directory")
+	flags.Parse(args)
+
+	raceService, err := openRaceService(*storagePath)
+	if err != nil {
+		return err
+	}
+
+	races, err := raceService.ListRaces()
+	if err != nil {
+		return err
+	}
+	for _, race := range races {
+		fmt.Printf("%s %s %s v%d competitors: %d events: %d protests: %d\n", race.ID, race.CreatedAt.Format(time.DateTime), race.Status, race.Version, len(race.Competitors), len(race.Events), len(race.Protests))
+	}
+
+	return nil
+}
+
+// exportRace повторно выгружает итоговую таблицу сохранённой гонки в файл или в стандартный вывод.
+func exportRace(args []string) error {
+	flags := flag.NewFlagSet("export", flag.ExitOnError)
+	storagePath := flags.String("storage", defaultStoragePath, "path to races storage directory")
+	raceID := flags.String("id", "", "race identifier")
+	outputPath := flags.String("o", "", "path to output file (stdout by default)")
+	locale := flags.String("locale", messages.DefaultLocale, "language of the report headers: en or ru")
+	flags.Parse(args)
+
+	if *raceID == "" {
+		return fmt.Errorf("race identifier is required")
+	}
+	catalog, err := messages.New(*locale)
+	if err != nil {
+		return err
+	}
+
+	raceService, err := openRaceService(*storagePath)
+	if err != nil {
+		return err
+	}
+
+	output := os.Stdout
+	if *outputPath != "" {
+		output, err = os.Create(*outputPath)
+		if err != nil {
+			return fmt.Errorf("failed to create output file: %w", err)
+		}
+		defer output.Close()
+	}
+
+	return raceService.ExportRace(*raceID, output, catalog)
+}
+
+// publishRace публикует неофициальные результаты гонки как предварительные.
+func publishRace(args []string) error {
+	flags := flag.NewFlagSet("publish", flag.ExitOnError)
+	storagePath := flags.String("storage", defaultStoragePath, "path to races storage directory")
+	raceID := flags.String("id", "", "race identifier")
+	flags.Parse(args)
+
+	raceService, err := openRaceService(*storagePath)
+	if err != nil {
+		return err
+	}
+	race, err := raceService.PublishProvisional(*raceID)
+	if err != nil {
+		return err
+	}
+	deadline, ok := services.ProtestDeadline(race)
+	if !ok {
+		fmt.Printf("%s %s v%d, no competitor has finished\n", race.ID, race.Status, race.Version)
+		return nil
+	}
+	fmt.Printf("%s %s v%d, protest window closes at %s\n", race.ID, race.Status, race.Version, deadline.Format("15:04:05.000"))
+
+	return nil
+}
+
+// fileProtest прикрепляет протест к участнику гонки.
+func fileProtest(args []string) error {
+	flags := flag.NewFlagSet("protest", flag.ExitOnError)
+	storagePath := flags.String("storage", defaultStoragePath, "path to races storage directory")
+	raceID := flags.String("id", "", "race identifier")
+	competitorID := flags.String("competitor", "", "competitor identifier")
+	at := flags.String("at", time.Now().Format("15:04:05.000"), "race clock time of submission (15:04:05.000 or 2006-01-02T15:04:05.000)")
+	filedBy := flags.String("by", "", "who files the protest")
+	reason := flags.String("reason", "", "reason of the protest")
+	flags.Parse(args)
+
+	raceService, err := openRaceService(*storagePath)
+	if err != nil {
+		return err
+	}
+	race, err := raceService.GetRace(*raceID)
+	if err != nil {
+		return err
+	}
+	submittedAt, err := services.ParseRaceTime(race, *at)
+	if err != nil {
+		return fmt.Errorf("failed to parse submission time: %w", err)
+	}
+	race, err = raceService.FileProtest(*raceID, &entities.Protest{
+		CompetitorID: *competitorID,
+		SubmittedAt:  submittedAt,
+		FiledBy:      *filedBy,
+		Reason:       *reason,
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s protest %d filed\n", race.ID, len(race.Protests))
+
+	return nil
+}
+
+// decideProtest фиксирует решение жюри по протесту.
+func decideProtest(args []string) error {
+	flags := flag.NewFlagSet("decide", flag.ExitOnError)
+	storagePath := flags.String("storage", defaultStoragePath, "path to races storage directory")
+	raceID := flags.String("id", "", "race identifier")
+	index := flags.Int("protest", 0, "protest number")
+	decision := flags.String("decision", "", "decision: accepted or rejected")
+	decidedBy := flags.String("by", "", "who decided")
+	flags.Parse(args)
+
+	raceService, err := openRaceService(*storagePath)
+	if err != nil {
+		return err
+	}
+	race, err := raceService.DecideProtest(*raceID, *index, *decision, *decidedBy)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s %s v%d\n", race.ID, race.Status, race.Version)
+
+	return nil
+}
+
+// makeOfficial утверждает предварительные результаты гонки.
+func makeOfficial(args []string) error {
+	flags := flag.NewFlagSet("official", flag.ExitOnError)
+	storagePath := flags.String("storage", defaultStoragePath, "path to races storage directory")
+	raceID := flags.String("id", "", "race identifier")
+	at := flags.String("at", time.Now().Format("15:04:05.000"), "current race clock time (15:04:05.000 or 2006-01-02T15:04:05.000)")
+	flags.Parse(args)
+
+	raceService, err := openRaceService(*storagePath)
+	if err != nil {
+		return err
+	}
+	race, err := raceService.GetRace(*raceID)
+	if err != nil {
+		return err
+	}
+	now, err := services.ParseRaceTime(race, *at)
+	if err != nil {
+		return fmt.Errorf("failed to parse current time: %w", err)
+	}
+	race, err = raceService.MakeOfficial(*raceID, now)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s %s v%d\n", race.ID, race.Status, race.Version)
+
+	return nil
+}
+
+// openRaceService открывает файловое хранилище гонок и создаёт сервис для работы с ними.
+func openRaceService(storagePath string) (*services.RaceService, error) {
+	fileStorage, err := storage.NewFileStorage(storagePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open storage: %w", err)
+	}
+
+	return services.NewRaceService(fileStorage), nil
 }
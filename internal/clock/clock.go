@@ -0,0 +1,141 @@
+package clock
+
+import (
+	"cmp"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Midnight — начало суток гонки: дата, на которую разбирается время событий без даты.
+var Midnight = time.Date(0, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// Clock описывает часы гонки. Обработчик событий использует только время суток,
+// показываемое часами, и переносит его на текущие сутки гонки (см. Resolve).
+type Clock interface {
+	Now() time.Time
+}
+
+// WallClock представляет часы гонки, идущие по системному времени. Используется при работе в реальном времени.
+type WallClock struct{}
+
+// Now возвращает текущее системное время суток в формате часов гонки.
+func (WallClock) Now() time.Time {
+	now := time.Now()
+
+	sinceMidnight := time.Duration(now.Hour())*time.Hour + time.Duration(now.Minute())*time.Minute +
+		time.Duration(now.Second())*time.Second + time.Duration(now.Nanosecond())
+
+	return Midnight.Add(sinceMidnight)
+}
+
+// SimulatedClock представляет часы гонки, время которых задаётся явно.
+// Используется при воспроизведении записанных событий и в тестах.
+type SimulatedClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+// NewSimulatedClock создаёт симулированные часы, показывающие время now.
+func NewSimulatedClock(now time.Time) *SimulatedClock {
+	return &SimulatedClock{now: now}
+}
+
+// Now возвращает текущее время симулированных часов.
+func (c *SimulatedClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+// Set переводит симулированные часы на время now. Часы не идут назад.
+func (c *SimulatedClock) Set(now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if now.After(c.now) {
+		c.now = now
+	}
+}
+
+// Advance переводит симулированные часы вперёд на d.
+func (c *SimulatedClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if d > 0 {
+		c.now = c.now.Add(d)
+	}
+}
+
+// timer представляет отложенное действие, которое должно выполниться в момент deadline.
+type timer struct {
+	key      string
+	deadline time.Time
+	action   func(deadline time.Time)
+}
+
+// Timers представляет набор таймеров, срабатывающих по часам гонки.
+// Таймеры не запускаются сами: владелец вызывает Fire с текущим временем часов,
+// и все просроченные таймеры выполняются в порядке их сроков.
+type Timers struct {
+	mu     sync.Mutex
+	timers map[string]*timer
+}
+
+// NewTimers создаёт пустой набор таймеров.
+func NewTimers() *Timers {
+	return &Timers{timers: make(map[string]*timer)}
+}
+
+// Schedule назначает действие action на момент deadline под ключом key,
+// заменяя ранее назначенный таймер с тем же ключом.
+func (t *Timers) Schedule(key string, deadline time.Time, action func(deadline time.Time)) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.timers[key] = &timer{key: key, deadline: deadline, action: action}
+}
+
+// Cancel отменяет таймер с ключом key, если он ещё не сработал.
+func (t *Timers) Cancel(key string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	delete(t.timers, key)
+}
+
+// Fire выполняет все таймеры, срок которых строго раньше now, в порядке их сроков
+// (при равных сроках — в порядке ключей), и возвращает количество сработавших таймеров.
+func (t *Timers) Fire(now time.Time) int {
+	t.mu.Lock()
+	var due []*timer
+	for key, timer := range t.timers {
+		if timer.deadline.Before(now) {
+			due = append(due, timer)
+			delete(t.timers, key)
+		}
+	}
+	t.mu.Unlock()
+
+	slices.SortFunc(due, func(a, b *timer) int {
+		if c := a.deadline.Compare(b.deadline); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.key, b.key)
+	})
+	for _, timer := range due {
+		timer.action(timer.deadline)
+	}
+
+	return len(due)
+}
+
+// Len возвращает количество ожидающих таймеров.
+func (t *Timers) Len() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return len(t.timers)
+}
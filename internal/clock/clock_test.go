@@ -0,0 +1,77 @@
+package clock_test
+
+import (
+	"system_prototype_for_biathlon_competitions/internal/clock"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestSimulatedClock тестирует симулированные часы.
+func TestSimulatedClock(t *testing.T) {
+	raceClock := clock.NewSimulatedClock(clock.Midnight.Add(10 * time.Hour))
+
+	raceClock.Advance(time.Minute)
+	require.Equal(t, clock.Midnight.Add(10*time.Hour+time.Minute), raceClock.Now())
+
+	raceClock.Set(clock.Midnight.Add(9 * time.Hour))
+	require.Equal(t, clock.Midnight.Add(10*time.Hour+time.Minute), raceClock.Now(), "clock must not go back")
+
+	raceClock.Set(clock.Midnight.Add(11 * time.Hour))
+	require.Equal(t, clock.Midnight.Add(11*time.Hour), raceClock.Now())
+}
+
+// TestTimers тестирует срабатывание и отмену таймеров.
+func TestTimers(t *testing.T) {
+	var fired []string
+	record := func(key string) func(time.Time) {
+		return func(time.Time) { fired = append(fired, key) }
+	}
+
+	timers := clock.NewTimers()
+	timers.Schedule("b", clock.Midnight.Add(2*time.Minute), record("b"))
+	timers.Schedule("a", clock.Midnight.Add(3*time.Minute), record("a"))
+	timers.Schedule("c", clock.Midnight.Add(time.Minute), record("c"))
+	timers.Schedule("d", clock.Midnight.Add(time.Hour), record("d"))
+	timers.Cancel("d")
+
+	require.Zero(t, timers.Fire(clock.Midnight.Add(time.Minute)), "deadline equal to now is not due yet")
+	require.Equal(t, 2, timers.Fire(clock.Midnight.Add(150*time.Second)))
+	require.Equal(t, []string{"c", "b"}, fired)
+
+	timers.Schedule("e", clock.Midnight.Add(4*time.Minute), record("e"))
+	require.Equal(t, 1, timers.Fire(clock.Midnight.Add(4*time.Minute)))
+	require.Equal(t, []string{"c", "b", "a"}, fired)
+	require.Equal(t, 1, timers.Len())
+}
+
+// TestParseTimestamp тестирует разбор меток времени и переход через полночь.
+func TestParseTimestamp(t *testing.T) {
+	raceDay, err := clock.RaceDay("2024-01-15")
+	require.NoError(t, err)
+	lateEvening := raceDay.Add(23*time.Hour + 55*time.Minute)
+
+	tests := []struct {
+		value    string
+		ref      time.Time
+		expected time.Time
+	}{
+		{"10:00:00.000", raceDay, raceDay.Add(10 * time.Hour)},
+		{"23:54:59.000", lateEvening, raceDay.Add(23*time.Hour + 54*time.Minute + 59*time.Second)},
+		{"00:05:00.000", lateEvening, raceDay.AddDate(0, 0, 1).Add(5 * time.Minute)},
+		{"2024-01-17T08:00:00.000", lateEvening, raceDay.AddDate(0, 0, 2).Add(8 * time.Hour)},
+		{"00:05:00.000", clock.Midnight.Add(23 * time.Hour), clock.Midnight.AddDate(0, 0, 1).Add(5 * time.Minute)},
+	}
+
+	for _, tt := range tests {
+		result, err := clock.ParseTimestamp(tt.value, tt.ref)
+		require.NoError(t, err)
+		require.Equal(t, tt.expected, result, tt.value)
+	}
+
+	_, err = clock.ParseTimestamp("25:00:00.000", raceDay)
+	require.Error(t, err)
+	_, err = clock.RaceDay("15.01.2024")
+	require.Error(t, err)
+}
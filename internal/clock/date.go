@@ -0,0 +1,58 @@
+package clock
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Форматы меток времени.
+const (
+	DateLayout     = "2006-01-02"              // Дата гонки в конфигурации
+	TimeLayout     = "15:04:05.000"            // Время суток без даты
+	DateTimeLayout = "2006-01-02T15:04:05.000" // Время с явной датой
+)
+
+// DayRolloverThreshold задаёт порог перехода через полночь: если время суток оказывается раньше
+// опорного времени более чем на этот порог, считается, что наступили следующие сутки.
+// Меньшие отступления назад считаются нарушением порядка событий, а не сменой суток.
+const DayRolloverThreshold = 12 * time.Hour
+
+// RaceDay возвращает начало суток гонки по дате в формате DateLayout.
+// Если дата не указана, возвращается Midnight.
+func RaceDay(date string) (time.Time, error) {
+	if date == "" {
+		return Midnight, nil
+	}
+	day, err := time.Parse(DateLayout, date)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to parse race date: %w", err)
+	}
+
+	return day, nil
+}
+
+// ParseTimestamp разбирает метку времени в формате TimeLayout или DateTimeLayout.
+// Время с явной датой возвращается как есть, а время суток разрешается относительно ref функцией Resolve.
+func ParseTimestamp(value string, ref time.Time) (time.Time, error) {
+	if strings.Contains(value, "T") {
+		return time.Parse(DateTimeLayout, value)
+	}
+	timeOfDay, err := time.Parse(TimeLayout, value)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return Resolve(timeOfDay, ref), nil
+}
+
+// Resolve переносит время суток t на сутки опорного времени ref. Если результат оказывается
+// раньше ref более чем на DayRolloverThreshold, он переносится на следующие сутки.
+func Resolve(t, ref time.Time) time.Time {
+	resolved := time.Date(ref.Year(), ref.Month(), ref.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC)
+	if ref.Sub(resolved) > DayRolloverThreshold {
+		resolved = resolved.AddDate(0, 0, 1)
+	}
+
+	return resolved
+}
@@ -1,11 +1,66 @@
 package config
 
+// Режимы начисления штрафа за промахи.
+const (
+	PenaltyModeLaps = "laps" // За каждый промах проходится штрафной круг
+	PenaltyModeTime = "time" // За каждый промах к итоговому времени добавляется штрафное время
+)
+
+// Положения для стрельбы на огневом рубеже.
+const (
+	PositionProne    = "prone"    // Стрельба лёжа
+	PositionStanding = "standing" // Стрельба стоя
+)
+
+// Режимы обработки событий, нарушающих порядок времени.
+const (
+	EventOrderReject  = "reject"  // Обработка прерывается с номером строки (по умолчанию)
+	EventOrderReorder = "reorder" // События переупорядочиваются в пределах окна
+	EventOrderWarn    = "warn"    // События обрабатываются в порядке поступления с предупреждением
+)
+
+// Точность вывода времени в отчётах.
+const (
+	PrecisionMilliseconds = "ms"         // Тысячные доли секунды (по умолчанию)
+	PrecisionHundredths   = "hundredths" // Сотые доли секунды
+	PrecisionTenths       = "tenths"     // Десятые доли секунды
+	PrecisionSeconds      = "seconds"    // Целые секунды
+)
+
+// Способы приведения времени к точности вывода.
+const (
+	RoundingTruncate = "truncate" // Отбрасывание лишних разрядов, как в правилах FIS/IBU (по умолчанию)
+	RoundingRound    = "round"    // Округление до ближайшего значения
+)
+
+// Единицы измерения скорости в отчётах.
+const (
+	SpeedUnitMPS = "m/s"  // Метры в секунду (по умолчанию)
+	SpeedUnitKMH = "km/h" // Километры в час
+)
+
 // Config представляет конфигурацию для системы соревнований по биатлону.
 type Config struct {
-	Laps        int    `json:"laps"`        // Количество кругов в гонке
-	PenaltyLen  int    `json:"penaltyLen"`  // Длина штрафного круга (в метрах)
-	LapLen      int    `json:"lapLen"`      // Длина одного круга (в метрах)
-	FiringLines int    `json:"firingLines"` // Количество огневых рубежей
-	Start       string `json:"start"`       // Время начала гонки
-	StartDelta  string `json:"startDelta"`  // Интервал между стартами участников
+	Laps        int         `json:"laps" yaml:"laps,omitempty" toml:"laps,omitempty,omitzero"`                      // Количество кругов в гонке
+	PenaltyLen  int         `json:"penaltyLen" yaml:"penaltyLen,omitempty" toml:"penaltyLen,omitempty,omitzero"`    // Длина штрафного круга (в метрах)
+	LapLen      int         `json:"lapLen" yaml:"lapLen,omitempty" toml:"lapLen,omitempty,omitzero"`                // Длина одного круга (в метрах)
+	FiringLines int         `json:"firingLines" yaml:"firingLines,omitempty" toml:"firingLines,omitempty,omitzero"` // Количество огневых рубежей
+	Course      []CourseLap `json:"course,omitempty" yaml:"course,omitempty" toml:"course,omitempty"`               // Описание кругов трассы (необязательно, по умолчанию Laps кругов длины LapLen)
+	Date        Date        `json:"date" yaml:"date,omitempty" toml:"date,omitempty,omitzero"`                      // Дата гонки в формате 2006-01-02 (необязательно)
+	Start       TimeOfDay   `json:"start" yaml:"start,omitempty" toml:"start,omitempty,omitzero"`                   // Время начала гонки
+	StartDelta  Duration    `json:"startDelta" yaml:"startDelta,omitempty" toml:"startDelta,omitempty,omitzero"`    // Интервал между стартами участников
+	PenaltyMode string      `json:"penaltyMode" yaml:"penaltyMode,omitempty" toml:"penaltyMode,omitempty,omitzero"` // Режим штрафа за промахи: "laps" (по умолчанию) или "time"
+	PenaltyTime Duration    `json:"penaltyTime" yaml:"penaltyTime,omitempty" toml:"penaltyTime,omitempty,omitzero"` // Штрафное время за один промах в режиме "time"
+
+	PenaltyLoopMaxSpeed float64  `json:"penaltyLoopMaxSpeed" yaml:"penaltyLoopMaxSpeed,omitempty" toml:"penaltyLoopMaxSpeed,omitempty,omitzero"` // Максимальная правдоподобная скорость на штрафном круге (м/с), по умолчанию 10
+	SkippedLoopPenalty  Duration `json:"skippedLoopPenalty" yaml:"skippedLoopPenalty,omitempty" toml:"skippedLoopPenalty,omitempty,omitzero"`    // Автоматический штраф за каждый непройденный штрафной круг (необязательно)
+	ProtestWindow       Duration `json:"protestWindow" yaml:"protestWindow,omitempty" toml:"protestWindow,omitempty,omitzero"`                   // Окно подачи протестов после финиша последнего участника, по умолчанию 00:15:00
+	CutoffTime          Duration `json:"cutoffTime" yaml:"cutoffTime,omitempty" toml:"cutoffTime,omitempty,omitzero"`                            // Лимит времени на трассе от фактического старта (необязательно)
+	EventOrder          string   `json:"eventOrder" yaml:"eventOrder,omitempty" toml:"eventOrder,omitempty,omitzero"`                            // Обработка нарушений порядка событий: "reject" (по умолчанию), "reorder" или "warn"
+	ReorderWindow       Duration `json:"reorderWindow" yaml:"reorderWindow,omitempty" toml:"reorderWindow,omitempty,omitzero"`                   // Окно переупорядочивания событий в режиме "reorder"
+
+	TimePrecision string `json:"timePrecision" yaml:"timePrecision,omitempty" toml:"timePrecision,omitempty,omitzero"` // Точность вывода времени: "ms" (по умолчанию), "hundredths", "tenths" или "seconds"
+	TimeRounding  string `json:"timeRounding" yaml:"timeRounding,omitempty" toml:"timeRounding,omitempty,omitzero"`    // Приведение времени к точности: "truncate" (по умолчанию) или "round"
+	ElideHours    bool   `json:"elideHours" yaml:"elideHours,omitempty" toml:"elideHours,omitempty,omitzero"`          // Не выводить часы для времени меньше часа
+	SpeedUnit     string `json:"speedUnit" yaml:"speedUnit,omitempty" toml:"speedUnit,omitempty,omitzero"`             // Единица скорости: "m/s" (по умолчанию) или "km/h"
 }
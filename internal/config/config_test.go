@@ -0,0 +1,251 @@
+package config_test
+
+import (
+	"encoding/json"
+	"strings"
+	"system_prototype_for_biathlon_competitions/internal/config"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestLoad тестирует чтение, заполнение значений по умолчанию и проверку конфигурации.
+func TestLoad(t *testing.T) {
+	t.Run("valid config", func(t *testing.T) {
+		cfg, err := config.Load(strings.NewReader(`{"laps": 2, "lapLen": 3500, "penaltyLen": 150, "firingLines": 2,
+			"start": "10:00:00.000", "startDelta": "00:01:30", "date": "2024-01-15", "skippedLoopPenalty": "00:00:30.500"}`))
+		require.NoError(t, err)
+		require.Equal(t, &config.Config{
+			Laps:                2,
+			LapLen:              3500,
+			PenaltyLen:          150,
+			FiringLines:         2,
+			Date:                config.Date(time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)),
+			Start:               config.TimeOfDay(10 * time.Hour),
+			StartDelta:          config.Duration(90 * time.Second),
+			PenaltyMode:         config.PenaltyModeLaps,
+			PenaltyLoopMaxSpeed: config.DefaultPenaltyLoopMaxSpeed,
+			SkippedLoopPenalty:  config.Duration(30*time.Second + 500*time.Millisecond),
+			ProtestWindow:       config.Duration(config.DefaultProtestWindow),
+			EventOrder:          config.EventOrderReject,
+			TimePrecision:       config.PrecisionMilliseconds,
+			TimeRounding:        config.RoundingTruncate,
+			SpeedUnit:           config.SpeedUnitMPS,
+		}, cfg)
+		require.Equal(t, time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC), cfg.Date.Day())
+	})
+
+	t.Run("field errors", func(t *testing.T) {
+		cfg, err := config.Load(strings.NewReader(`{"laps": -1, "lapLen": "long", "penaltyLen": 150, "lapLenn": 3500,
+			"start": "10:00:00.000", "startDelta": "1:30", "eventOrder": "reorder", "speedUnit": "mph"}`))
+		require.NotNil(t, cfg)
+		var fieldErrors config.FieldErrors
+		require.ErrorAs(t, err, &fieldErrors)
+		require.EqualError(t, err, `lapLen: expected number, found string; `+
+			`lapLenn: unknown field; `+
+			`startDelta: expected duration HH:MM:SS, found "1:30"; `+
+			`firingLines: is required; `+
+			`laps: must be positive, found -1; `+
+			`reorderWindow: is required in "reorder" event order mode; `+
+			`speedUnit: expected "m/s" or "km/h", found "mph"`)
+	})
+
+	t.Run("invalid json", func(t *testing.T) {
+		cfg, err := config.Load(strings.NewReader(`{"laps": 2,`))
+		require.Nil(t, cfg)
+		require.ErrorContains(t, err, "failed to decode json")
+	})
+}
+
+// TestConfigJSON тестирует запись конфигурации в JSON и её обратное чтение.
+func TestConfigJSON(t *testing.T) {
+	cfg := &config.Config{
+		Laps:        2,
+		LapLen:      3500,
+		PenaltyLen:  150,
+		FiringLines: 2,
+		Start:       config.TimeOfDay(10 * time.Hour),
+		StartDelta:  config.Duration(90 * time.Second),
+		PenaltyMode: config.PenaltyModeTime,
+		PenaltyTime: config.Duration(time.Minute),
+	}
+	cfg.SetDefaults()
+
+	data, err := json.Marshal(cfg)
+	require.NoError(t, err)
+	require.Contains(t, string(data), `"date":"","start":"10:00:00.000","startDelta":"00:01:30"`)
+
+	loaded, err := config.Load(strings.NewReader(string(data)))
+	require.NoError(t, err)
+	require.Equal(t, cfg, loaded)
+}
+
+// TestLoadFormat тестирует чтение конфигурации в форматах YAML и TOML и определение формата.
+func TestLoadFormat(t *testing.T) {
+	expected, err := config.Load(strings.NewReader(`{"laps": 2, "lapLen": 3500, "penaltyLen": 150, "firingLines": 2,
+		"date": "2024-01-15", "start": "10:00:00.000", "startDelta": "00:01:30", "elideHours": true}`))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		file    string
+		content string
+	}{
+		{
+			name: "yaml",
+			file: "config.yaml",
+			content: `# Sprint, 2 laps
+laps: 2
+lapLen: 3500
+penaltyLen: 150
+firingLines: 2
+date: 2024-01-15
+start: "10:00:00.000"
+startDelta: "00:01:30"
+elideHours: true
+`,
+		},
+		{
+			name: "toml",
+			file: "config.toml",
+			content: `# Sprint, 2 laps
+laps = 2
+lapLen = 3500
+penaltyLen = 150
+firingLines = 2
+date = 2024-01-15
+start = 10:00:00.000
+startDelta = "00:01:30"
+elideHours = true
+`,
+		},
+		{
+			name:    "json without extension",
+			file:    "config",
+			content: `{"laps": 2, "lapLen": 3500, "penaltyLen": 150, "firingLines": 2, "date": "2024-01-15", "start": "10:00:00.000", "startDelta": "00:01:30", "elideHours": true}`,
+		},
+		{
+			name:    "toml without extension",
+			file:    "config.conf",
+			content: "laps = 2\nlapLen = 3500\npenaltyLen = 150\nfiringLines = 2\ndate = \"2024-01-15\"\nstart = \"10:00:00.000\"\nstartDelta = \"00:01:30\"\nelideHours = true\n",
+		},
+		{
+			name:    "yaml without extension",
+			file:    "config",
+			content: "laps: 2\nlapLen: 3500\npenaltyLen: 150\nfiringLines: 2\ndate: \"2024-01-15\"\nstart: \"10:00:00.000\"\nstartDelta: \"00:01:30\"\nelideHours: true\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := config.LoadFile(tt.file, strings.NewReader(tt.content))
+			require.NoError(t, err)
+			require.Equal(t, expected, cfg)
+		})
+	}
+
+	t.Run("same field errors", func(t *testing.T) {
+		const expectedErr = `firingLines: expected number, found string; lapLenn: unknown field; ` +
+			`startDelta: expected duration HH:MM:SS, found "1:30"; laps: must be positive, found -1`
+		_, err := config.LoadFormat(strings.NewReader(`{"laps": -1, "lapLen": 3500, "lapLenn": 1, "penaltyLen": 150,
+			"firingLines": "two", "start": "10:00:00.000", "startDelta": "1:30"}`), config.FormatJSON)
+		require.EqualError(t, err, expectedErr)
+		_, err = config.LoadFormat(strings.NewReader("laps: -1\nlapLen: 3500\nlapLenn: 1\npenaltyLen: 150\n"+
+			"firingLines: two\nstart: \"10:00:00.000\"\nstartDelta: \"1:30\"\n"), config.FormatYAML)
+		require.EqualError(t, err, expectedErr)
+		_, err = config.LoadFormat(strings.NewReader("laps = -1\nlapLen = 3500\nlapLenn = 1\npenaltyLen = 150\n"+
+			"firingLines = \"two\"\nstart = \"10:00:00.000\"\nstartDelta = \"1:30\"\n"), config.FormatTOML)
+		require.EqualError(t, err, expectedErr)
+	})
+
+	t.Run("syntax errors", func(t *testing.T) {
+		_, err := config.LoadFormat(strings.NewReader("laps: [2"), config.FormatYAML)
+		require.ErrorContains(t, err, "failed to decode yaml")
+		_, err = config.LoadFormat(strings.NewReader("laps = "), config.FormatTOML)
+		require.ErrorContains(t, err, "failed to decode toml")
+		_, err = config.LoadFormat(strings.NewReader("laps = 2"), "ini")
+		require.EqualError(t, err, `unsupported config format "ini"`)
+	})
+}
+
+// TestEncode тестирует перевод конфигурации во все форматы и её обратное чтение.
+func TestEncode(t *testing.T) {
+	cfg := &config.Config{
+		Laps:          2,
+		LapLen:        3500,
+		PenaltyLen:    150,
+		FiringLines:   2,
+		Date:          config.Date(time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)),
+		Start:         config.TimeOfDay(10 * time.Hour),
+		StartDelta:    config.Duration(90 * time.Second),
+		EventOrder:    config.EventOrderReorder,
+		ReorderWindow: config.Duration(500 * time.Millisecond),
+	}
+	cfg.SetDefaults()
+
+	for _, format := range config.Formats {
+		t.Run(format, func(t *testing.T) {
+			var output strings.Builder
+			require.NoError(t, config.Encode(&output, cfg, format))
+			require.Contains(t, output.String(), "00:00:00.500")
+			if format != config.FormatJSON {
+				require.NotContains(t, output.String(), "penaltyTime")
+			}
+
+			loaded, err := config.LoadFormat(strings.NewReader(output.String()), format)
+			require.NoError(t, err)
+			require.Equal(t, cfg, loaded)
+		})
+	}
+}
+
+// TestCourse тестирует описание трассы с кругами разной длины.
+func TestCourse(t *testing.T) {
+	t.Run("laps and firing lines from course", func(t *testing.T) {
+		cfg, err := config.Load(strings.NewReader(`{"lapLen": 3000, "penaltyLen": 150, "start": "10:00:00.000", "startDelta": "00:00:30",
+			"course": [{"length": 2500, "firingLine": 1}, {"firingLine": 2}, {"length": 3300}]}`))
+		require.NoError(t, err)
+		require.Equal(t, 3, cfg.Laps)
+		require.Equal(t, 2, cfg.FiringLines)
+		require.Equal(t, []int{2500, 3000, 3300}, []int{cfg.LapLength(1), cfg.LapLength(2), cfg.LapLength(3)})
+		require.Equal(t, 8800, cfg.Distance())
+
+		firingLine, ok := cfg.FiringLineOnLap(2)
+		require.True(t, ok)
+		require.Equal(t, 2, firingLine)
+		firingLine, ok = cfg.FiringLineOnLap(3)
+		require.True(t, ok)
+		require.Zero(t, firingLine)
+	})
+
+	t.Run("shooting positions", func(t *testing.T) {
+		cfg := &config.Config{Laps: 3, Course: []config.CourseLap{{FiringLine: 1, Position: config.PositionProne}, {FiringLine: 1, Position: config.PositionProne}, {}}}
+		require.Equal(t, config.PositionProne, cfg.ShootingPosition(2, 2))
+		require.Equal(t, config.PositionStanding, cfg.ShootingPosition(3, 2))
+		require.Equal(t, config.PositionProne, (&config.Config{}).ShootingPosition(1, 3))
+	})
+
+	t.Run("without course", func(t *testing.T) {
+		cfg := &config.Config{Laps: 2, LapLen: 3500}
+		require.Equal(t, 3500, cfg.LapLength(2))
+		require.Equal(t, 7000, cfg.Distance())
+		_, ok := cfg.FiringLineOnLap(1)
+		require.False(t, ok)
+	})
+
+	t.Run("course errors", func(t *testing.T) {
+		_, err := config.Load(strings.NewReader(`{"laps": 2, "firingLines": 1, "penaltyLen": 150, "start": "10:00:00.000", "startDelta": "00:00:30",
+			"course": [{"length": 2500, "firingLine": 2}, {"firingLine": 1}, {"length": 3300}]}`))
+		require.EqualError(t, err, `course: expected 2 laps, found 3; `+
+			`course: lap 1: firing line must be 0-1, found 2; `+
+			`course: lap 2: length must be positive, found 0`)
+
+		_, err = config.Load(strings.NewReader(`{"laps": 1, "lapLen": 3000, "penaltyLen": 150, "start": "10:00:00.000", "startDelta": "00:00:30",
+			"course": [{"firingLine": 1, "position": "kneeling"}]}`))
+		require.EqualError(t, err, `course: lap 1: position must be "prone" or "standing", found "kneeling"`)
+
+		_, err = config.Load(strings.NewReader(`{"penaltyLen": 150, "start": "10:00:00.000", "startDelta": "00:00:30",
+			"course": [{"lenght": 2500}]}`))
+		require.EqualError(t, err, `course: unknown field "lenght"; firingLines: must be positive, found 0`)
+	})
+}
@@ -0,0 +1,80 @@
+package config
+
+import "fmt"
+
+// CourseLap представляет собой описание одного круга трассы.
+type CourseLap struct {
+	Length     int    `json:"length,omitempty" yaml:"length,omitempty" toml:"length,omitempty,omitzero"`             // Длина круга (в метрах), по умолчанию LapLen
+	FiringLine int    `json:"firingLine,omitempty" yaml:"firingLine,omitempty" toml:"firingLine,omitempty,omitzero"` // Огневой рубеж в конце круга (0 — круг без стрельбы)
+	Position   string `json:"position,omitempty" yaml:"position,omitempty" toml:"position,omitempty,omitzero"`       // Положение для стрельбы: "prone" или "standing" (по умолчанию по очереди, начиная с "prone")
+}
+
+// LapLength возвращает длину круга с номером lap (с 1): длину из описания трассы
+// или, если она не указана, LapLen.
+func (c *Config) LapLength(lap int) int {
+	if lap >= 1 && lap <= len(c.Course) && c.Course[lap-1].Length > 0 {
+		return c.Course[lap-1].Length
+	}
+
+	return c.LapLen
+}
+
+// FiringLineOnLap возвращает огневой рубеж, на котором участник стреляет в конце круга с номером lap (с 1).
+// Второй результат равен false, если трасса не описана и рубеж неизвестен.
+func (c *Config) FiringLineOnLap(lap int) (int, bool) {
+	if len(c.Course) == 0 {
+		return 0, false
+	}
+	if lap < 1 || lap > len(c.Course) {
+		return 0, true
+	}
+
+	return c.Course[lap-1].FiringLine, true
+}
+
+// ShootingPosition возвращает положение для стрельбы на рубеже в конце круга с номером lap (с 1),
+// который является стрельбой с номером bout (с 1): положение из описания трассы или,
+// если оно не указано, "prone" для нечётных и "standing" для чётных стрельб.
+func (c *Config) ShootingPosition(lap, bout int) string {
+	if lap >= 1 && lap <= len(c.Course) && c.Course[lap-1].Position != "" {
+		return c.Course[lap-1].Position
+	}
+	if bout%2 == 0 {
+		return PositionStanding
+	}
+
+	return PositionProne
+}
+
+// Distance возвращает длину основной дистанции (в метрах) без штрафных кругов.
+func (c *Config) Distance() int {
+	distance := 0
+	for lap := 1; lap <= c.Laps; lap++ {
+		distance += c.LapLength(lap)
+	}
+
+	return distance
+}
+
+// validateCourse добавляет ошибки описания трассы: длина каждого круга должна быть известна,
+// огневые рубежи — в пределах FiringLines, положения для стрельбы — известны, а число кругов — совпадать с Laps.
+func (c *Config) validateCourse(fieldErrors *FieldErrors) {
+	if len(c.Course) == 0 {
+		return
+	}
+	if c.Laps != len(c.Course) {
+		fieldErrors.add("course", "expected %d laps, found %d", c.Laps, len(c.Course))
+	}
+	for i, lap := range c.Course {
+		prefix := fmt.Sprintf("lap %d: ", i+1)
+		if lap.Length < 0 || (lap.Length == 0 && c.LapLen <= 0) {
+			fieldErrors.add("course", "%slength must be positive, found %d", prefix, lap.Length)
+		}
+		if lap.FiringLine < 0 || (c.FiringLines > 0 && lap.FiringLine > c.FiringLines) {
+			fieldErrors.add("course", "%sfiring line must be 0-%d, found %d", prefix, c.FiringLines, lap.FiringLine)
+		}
+		if lap.Position != "" && lap.Position != PositionProne && lap.Position != PositionStanding {
+			fieldErrors.add("course", "%sposition must be %q or %q, found %q", prefix, PositionProne, PositionStanding, lap.Position)
+		}
+	}
+}
@@ -0,0 +1,167 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"system_prototype_for_biathlon_competitions/internal/clock"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// Config file formats:
+//
+// JSON:
+// {"laps": 2, "lapLen": 3651, "start": "09:30:00.000", "startDelta": "00:00:30"}
+//
+// YAML (comments are allowed):
+// laps: 2
+// lapLen: 3651
+// start: "09:30:00.000"
+// startDelta: "00:00:30"
+//
+// TOML (unquoted dates and times of day are accepted too):
+// laps = 2
+// lapLen = 3651
+// start = 09:30:00.000
+// startDelta = "00:00:30"
+
+// Форматы конфигурационного файла.
+const (
+	FormatJSON = "json"
+	FormatYAML = "yaml"
+	FormatTOML = "toml"
+)
+
+// Formats перечисляет поддерживаемые форматы конфигурационного файла.
+var Formats = []string{FormatJSON, FormatYAML, FormatTOML}
+
+// formatExtensions сопоставляет расширения файлов форматам конфигурации.
+var formatExtensions = map[string]string{
+	".json": FormatJSON,
+	".yaml": FormatYAML,
+	".yml":  FormatYAML,
+	".toml": FormatTOML,
+}
+
+// tomlKeyValue распознаёт строку TOML вида key = value или заголовок таблицы [table].
+var tomlKeyValue = regexp.MustCompile(`^\s*([A-Za-z0-9_-]+\s*=|\[[A-Za-z0-9_.-]+\]\s*$)`)
+
+// FormatByName возвращает формат конфигурации по расширению файла name или пустую строку,
+// если расширение не соответствует ни одному формату.
+func FormatByName(name string) string {
+	return formatExtensions[strings.ToLower(filepath.Ext(name))]
+}
+
+// DetectFormat определяет формат конфигурации по расширению файла name, а если расширение
+// не соответствует ни одному формату — по содержимому data: объект в фигурных скобках считается JSON,
+// строки вида key = value — TOML, остальное — YAML.
+func DetectFormat(name string, data []byte) string {
+	if format := FormatByName(name); format != "" {
+		return format
+	}
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("{")) {
+		return FormatJSON
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if line = strings.TrimSpace(line); line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if tomlKeyValue.MatchString(line) {
+			return FormatTOML
+		}
+		break
+	}
+
+	return FormatYAML
+}
+
+// LoadFile считывает конфигурацию из файла name в формате, определённом DetectFormat,
+// и проверяет её так же, как Load.
+func LoadFile(name string, r io.Reader) (*Config, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
+
+	return LoadFormat(bytes.NewReader(data), DetectFormat(name, data))
+}
+
+// LoadFormat считывает конфигурацию в формате format (FormatJSON, FormatYAML или FormatTOML)
+// и проверяет её так же, как Load: значения YAML и TOML приводятся к значениям JSON,
+// поэтому ошибки в полях одинаковы для всех форматов.
+func LoadFormat(r io.Reader, format string) (*Config, error) {
+	if format == FormatJSON {
+		return Load(r)
+	}
+
+	values := map[string]any{}
+	switch format {
+	case FormatYAML:
+		if err := yaml.NewDecoder(r).Decode(&values); err != nil && err != io.EOF {
+			return nil, fmt.Errorf("failed to decode yaml: %w", err)
+		}
+	case FormatTOML:
+		if _, err := toml.NewDecoder(r).Decode(&values); err != nil {
+			return nil, fmt.Errorf("failed to decode toml: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported config format %q", format)
+	}
+
+	raw := make(map[string]json.RawMessage, len(values))
+	for key, value := range values {
+		data, err := json.Marshal(normalizeValue(value))
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode %s: %s: %w", format, key, err)
+		}
+		raw[key] = data
+	}
+
+	return load(raw)
+}
+
+// normalizeValue приводит даты и время суток, разобранные YAML и TOML, к строкам
+// в форматах конфигурации: дата без времени — YYYY-MM-DD, время суток — HH:MM:SS.sss.
+func normalizeValue(value any) any {
+	t, ok := value.(time.Time)
+	if !ok {
+		return value
+	}
+	switch {
+	case t.Location().String() == "time-local":
+		return t.Format(clock.TimeLayout)
+	case t.Hour() == 0 && t.Minute() == 0 && t.Second() == 0 && t.Nanosecond() == 0:
+		return t.Format(clock.DateLayout)
+	default:
+		return t.Format(clock.DateTimeLayout)
+	}
+}
+
+// Encode записывает конфигурацию cfg в формате format. В YAML и TOML неуказанные
+// необязательные значения (дата, длительности, ElideHours) не записываются.
+func Encode(w io.Writer, cfg *Config, format string) error {
+	switch format {
+	case FormatJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(cfg)
+	case FormatYAML:
+		encoder := yaml.NewEncoder(w)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(cfg); err != nil {
+			return err
+		}
+		return encoder.Close()
+	case FormatTOML:
+		return toml.NewEncoder(w).Encode(cfg)
+	default:
+		return fmt.Errorf("unsupported config format %q", format)
+	}
+}
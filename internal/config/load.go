@@ -0,0 +1,257 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Значения необязательных настроек по умолчанию.
+const (
+	DefaultPenaltyLoopMaxSpeed = 10.0             // Максимальная правдоподобная скорость на штрафном круге (м/с)
+	DefaultProtestWindow       = 15 * time.Minute // Окно подачи протестов после финиша последнего участника
+)
+
+// requiredFields перечисляет обязательные поля конфигурации (см. Config.requires).
+var requiredFields = []string{"laps", "lapLen", "penaltyLen", "firingLines", "start", "startDelta"}
+
+// FieldError представляет собой ошибку в значении поля конфигурации.
+type FieldError struct {
+	Field   string // Имя поля в конфигурационном файле
+	Message string // Описание ошибки
+}
+
+// Error возвращает строковое представление ошибки с именем поля.
+func (e *FieldError) Error() string {
+	return e.Field + ": " + e.Message
+}
+
+// FieldErrors представляет собой список ошибок в полях конфигурации.
+type FieldErrors []*FieldError
+
+// Error возвращает ошибки всех полей через точку с запятой.
+func (e FieldErrors) Error() string {
+	messages := make([]string, 0, len(e))
+	for _, fieldErr := range e {
+		messages = append(messages, fieldErr.Error())
+	}
+
+	return strings.Join(messages, "; ")
+}
+
+// add добавляет ошибку поля field с описанием по формату format.
+func (e *FieldErrors) add(field, format string, args ...any) {
+	*e = append(*e, &FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+// Load считывает конфигурацию в формате JSON, заполняет необязательные настройки значениями
+// по умолчанию и проверяет её. Ошибки в полях (неизвестные поля, отсутствующие обязательные поля,
+// неверные форматы и недопустимые значения) собираются все сразу и возвращаются как FieldErrors
+// вместе с прочитанной конфигурацией; при ошибке синтаксиса JSON конфигурация не возвращается.
+func Load(r io.Reader) (*Config, error) {
+	var raw map[string]json.RawMessage
+	if err := json.NewDecoder(r).Decode(&raw); err != nil {
+		return nil, fmt.Errorf("failed to decode json: %w", err)
+	}
+
+	return load(raw)
+}
+
+// load разбирает поля конфигурации raw, заданные значениями JSON, и проверяет конфигурацию.
+func load(raw map[string]json.RawMessage) (*Config, error) {
+	cfg := &Config{}
+	var fieldErrors FieldErrors
+	fields := jsonFields(cfg)
+	keys := make([]string, 0, len(raw))
+	for key := range raw {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	for _, key := range keys {
+		field, ok := fields[key]
+		if !ok {
+			fieldErrors.add(key, "unknown field")
+			continue
+		}
+		decoder := json.NewDecoder(bytes.NewReader(raw[key]))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(field.Addr().Interface()); err != nil {
+			fieldErrors.add(key, "%s", describeDecodeError(err))
+		}
+	}
+	for _, key := range requiredFields {
+		if _, ok := raw[key]; !ok && cfg.requires(key) {
+			fieldErrors.add(key, "is required")
+		}
+	}
+
+	cfg.SetDefaults()
+	if err := cfg.Validate(); err != nil {
+		var validationErrors FieldErrors
+		if !errors.As(err, &validationErrors) {
+			return nil, err
+		}
+		decodeErrors := slices.Clone(fieldErrors)
+		for _, validationErr := range validationErrors {
+			if !slices.ContainsFunc(decodeErrors, func(fieldErr *FieldError) bool { return fieldErr.Field == validationErr.Field }) {
+				fieldErrors = append(fieldErrors, validationErr)
+			}
+		}
+	}
+	if len(fieldErrors) > 0 {
+		return cfg, fieldErrors
+	}
+
+	return cfg, nil
+}
+
+// jsonFields возвращает поля конфигурации cfg по их именам в конфигурационном файле.
+func jsonFields(cfg *Config) map[string]reflect.Value {
+	value := reflect.ValueOf(cfg).Elem()
+	fields := make(map[string]reflect.Value, value.NumField())
+	for i := 0; i < value.NumField(); i++ {
+		name, _, _ := strings.Cut(value.Type().Field(i).Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			fields[name] = value.Field(i)
+		}
+	}
+
+	return fields
+}
+
+// requires сообщает, обязательно ли поле field при уже прочитанных значениях остальных полей:
+// длина штрафного круга не нужна в режиме штрафного времени, а число кругов, их длина
+// и число огневых рубежей следуют из описания трассы.
+func (c *Config) requires(field string) bool {
+	switch field {
+	case "penaltyLen":
+		return c.PenaltyMode != PenaltyModeTime
+	case "laps", "lapLen", "firingLines":
+		return len(c.Course) == 0
+	default:
+		return true
+	}
+}
+
+// describeDecodeError возвращает описание ошибки разбора значения поля.
+func describeDecodeError(err error) string {
+	if field, ok := strings.CutPrefix(err.Error(), "json: unknown field "); ok {
+		return "unknown field " + field
+	}
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		expected := typeErr.Type.Kind().String()
+		switch typeErr.Type.Kind() {
+		case reflect.Int, reflect.Int64, reflect.Float64:
+			expected = "number"
+		case reflect.Bool:
+			expected = "boolean"
+		}
+		message := fmt.Sprintf("expected %s, found %s", expected, typeErr.Value)
+		if typeErr.Field != "" {
+			message = typeErr.Field + ": " + message
+		}
+		return message
+	}
+
+	return err.Error()
+}
+
+// SetDefaults заполняет неуказанные необязательные настройки значениями по умолчанию.
+// Число кругов и огневых рубежей по умолчанию берётся из описания трассы.
+func (c *Config) SetDefaults() {
+	if c.Laps == 0 {
+		c.Laps = len(c.Course)
+	}
+	if c.FiringLines == 0 {
+		for _, lap := range c.Course {
+			c.FiringLines = max(c.FiringLines, lap.FiringLine)
+		}
+	}
+	if c.PenaltyMode == "" {
+		c.PenaltyMode = PenaltyModeLaps
+	}
+	if c.PenaltyLoopMaxSpeed == 0 {
+		c.PenaltyLoopMaxSpeed = DefaultPenaltyLoopMaxSpeed
+	}
+	if c.ProtestWindow == 0 {
+		c.ProtestWindow = Duration(DefaultProtestWindow)
+	}
+	if c.EventOrder == "" {
+		c.EventOrder = EventOrderReject
+	}
+	if c.TimePrecision == "" {
+		c.TimePrecision = PrecisionMilliseconds
+	}
+	if c.TimeRounding == "" {
+		c.TimeRounding = RoundingTruncate
+	}
+	if c.SpeedUnit == "" {
+		c.SpeedUnit = SpeedUnitMPS
+	}
+}
+
+// Validate проверяет значения конфигурации и возвращает ошибки всех недопустимых полей как FieldErrors.
+// Если указаны имена полей fields, проверяются только они. Пустые значения режимов
+// считаются значениями по умолчанию.
+func (c *Config) Validate(fields ...string) error {
+	var fieldErrors FieldErrors
+	check := func(field string) bool {
+		return len(fields) == 0 || slices.Contains(fields, field)
+	}
+
+	if check("laps") && c.Laps <= 0 {
+		fieldErrors.add("laps", "must be positive, found %d", c.Laps)
+	}
+	if check("lapLen") && (c.LapLen < 0 || (len(c.Course) == 0 && c.LapLen == 0)) {
+		fieldErrors.add("lapLen", "must be positive, found %d", c.LapLen)
+	}
+	if check("course") {
+		c.validateCourse(&fieldErrors)
+	}
+	if check("penaltyLen") && c.PenaltyMode != PenaltyModeTime && c.PenaltyLen <= 0 {
+		fieldErrors.add("penaltyLen", "must be positive, found %d", c.PenaltyLen)
+	}
+	if check("firingLines") && c.FiringLines <= 0 {
+		fieldErrors.add("firingLines", "must be positive, found %d", c.FiringLines)
+	}
+	if check("startDelta") && c.StartDelta <= 0 {
+		fieldErrors.add("startDelta", "must be positive")
+	}
+	if check("penaltyMode") && !slices.Contains([]string{"", PenaltyModeLaps, PenaltyModeTime}, c.PenaltyMode) {
+		fieldErrors.add("penaltyMode", "expected %q or %q, found %q", PenaltyModeLaps, PenaltyModeTime, c.PenaltyMode)
+	}
+	if check("penaltyTime") && c.PenaltyMode == PenaltyModeTime && c.PenaltyTime <= 0 {
+		fieldErrors.add("penaltyTime", "is required in %q penalty mode", PenaltyModeTime)
+	}
+	if check("penaltyLoopMaxSpeed") && c.PenaltyLoopMaxSpeed < 0 {
+		fieldErrors.add("penaltyLoopMaxSpeed", "must not be negative, found %g", c.PenaltyLoopMaxSpeed)
+	}
+	if check("eventOrder") && !slices.Contains([]string{"", EventOrderReject, EventOrderReorder, EventOrderWarn}, c.EventOrder) {
+		fieldErrors.add("eventOrder", "expected %q, %q or %q, found %q", EventOrderReject, EventOrderReorder, EventOrderWarn, c.EventOrder)
+	}
+	if check("reorderWindow") && c.EventOrder == EventOrderReorder && c.ReorderWindow <= 0 {
+		fieldErrors.add("reorderWindow", "is required in %q event order mode", EventOrderReorder)
+	}
+	if check("timePrecision") && !slices.Contains([]string{"", PrecisionMilliseconds, PrecisionHundredths, PrecisionTenths, PrecisionSeconds}, c.TimePrecision) {
+		fieldErrors.add("timePrecision", "expected %q, %q, %q or %q, found %q", PrecisionMilliseconds, PrecisionHundredths, PrecisionTenths, PrecisionSeconds, c.TimePrecision)
+	}
+	if check("timeRounding") && !slices.Contains([]string{"", RoundingTruncate, RoundingRound}, c.TimeRounding) {
+		fieldErrors.add("timeRounding", "expected %q or %q, found %q", RoundingTruncate, RoundingRound, c.TimeRounding)
+	}
+	if check("speedUnit") && !slices.Contains([]string{"", SpeedUnitMPS, SpeedUnitKMH}, c.SpeedUnit) {
+		fieldErrors.add("speedUnit", "expected %q or %q, found %q", SpeedUnitMPS, SpeedUnitKMH, c.SpeedUnit)
+	}
+
+	if len(fieldErrors) > 0 {
+		return fieldErrors
+	}
+
+	return nil
+}
@@ -0,0 +1,168 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"system_prototype_for_biathlon_competitions/internal/clock"
+	"time"
+)
+
+// Duration представляет собой длительность, записываемую в конфигурации как HH:MM:SS или HH:MM:SS.sss.
+type Duration time.Duration
+
+// ParseDuration разбирает длительность в формате HH:MM:SS или HH:MM:SS.sss.
+func ParseDuration(value string) (time.Duration, error) {
+	layout := "15:04:05"
+	if strings.Contains(value, ".") {
+		layout = "15:04:05.000"
+	}
+	t, err := time.Parse(layout, value)
+	if err != nil {
+		return 0, err
+	}
+
+	return t.Sub(clock.Midnight), nil
+}
+
+// String возвращает длительность в формате HH:MM:SS или, если есть доли секунды, HH:MM:SS.sss.
+func (d Duration) String() string {
+	layout := "15:04:05"
+	if time.Duration(d)%time.Second != 0 {
+		layout = "15:04:05.000"
+	}
+
+	return clock.Midnight.Add(time.Duration(d)).Format(layout)
+}
+
+// MarshalJSON записывает длительность строкой; нулевая длительность записывается пустой строкой.
+func (d Duration) MarshalJSON() ([]byte, error) {
+	if d == 0 {
+		return []byte(`""`), nil
+	}
+
+	return []byte(strconv.Quote(d.String())), nil
+}
+
+// MarshalText записывает длительность в формате HH:MM:SS для YAML и TOML.
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalJSON разбирает длительность из строки; пустая строка означает нулевую длительность.
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	value, err := unquote(data, "duration HH:MM:SS")
+	if err != nil || value == "" {
+		return err
+	}
+	duration, err := ParseDuration(value)
+	if err != nil {
+		return fmt.Errorf("expected duration HH:MM:SS, found %q", value)
+	}
+	*d = Duration(duration)
+
+	return nil
+}
+
+// TimeOfDay представляет собой время суток, записываемое в конфигурации как HH:MM:SS.sss.
+type TimeOfDay time.Duration
+
+// String возвращает время суток в формате HH:MM:SS.sss.
+func (t TimeOfDay) String() string {
+	return clock.Midnight.Add(time.Duration(t)).Format(clock.TimeLayout)
+}
+
+// MarshalJSON записывает время суток строкой.
+func (t TimeOfDay) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.Quote(t.String())), nil
+}
+
+// MarshalText записывает время суток в формате HH:MM:SS.sss для YAML и TOML.
+func (t TimeOfDay) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+// UnmarshalJSON разбирает время суток в формате HH:MM:SS.sss или HH:MM:SS.
+func (t *TimeOfDay) UnmarshalJSON(data []byte) error {
+	value, err := unquote(data, "time HH:MM:SS.sss")
+	if err != nil || value == "" {
+		return err
+	}
+	layout := clock.TimeLayout
+	if !strings.Contains(value, ".") {
+		layout = "15:04:05"
+	}
+	timeOfDay, err := time.Parse(layout, value)
+	if err != nil {
+		return fmt.Errorf("expected time HH:MM:SS.sss, found %q", value)
+	}
+	*t = TimeOfDay(timeOfDay.Sub(clock.Midnight))
+
+	return nil
+}
+
+// Date представляет собой дату гонки, записываемую в конфигурации как YYYY-MM-DD.
+// Нулевое значение означает, что дата не указана.
+type Date time.Time
+
+// IsZero сообщает, что дата не указана.
+func (d Date) IsZero() bool {
+	return time.Time(d).IsZero()
+}
+
+// Day возвращает начало суток гонки. Если дата не указана, возвращается clock.Midnight.
+func (d Date) Day() time.Time {
+	if d.IsZero() {
+		return clock.Midnight
+	}
+
+	return time.Time(d)
+}
+
+// String возвращает дату в формате YYYY-MM-DD или пустую строку, если дата не указана.
+func (d Date) String() string {
+	if d.IsZero() {
+		return ""
+	}
+
+	return time.Time(d).Format(clock.DateLayout)
+}
+
+// MarshalJSON записывает дату строкой; неуказанная дата записывается пустой строкой.
+func (d Date) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.Quote(d.String())), nil
+}
+
+// MarshalText записывает дату в формате YYYY-MM-DD для YAML и TOML.
+func (d Date) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalJSON разбирает дату в формате YYYY-MM-DD; пустая строка означает, что дата не указана.
+func (d *Date) UnmarshalJSON(data []byte) error {
+	value, err := unquote(data, "date YYYY-MM-DD")
+	if err != nil || value == "" {
+		return err
+	}
+	day, err := time.Parse(clock.DateLayout, value)
+	if err != nil {
+		return fmt.Errorf("expected date YYYY-MM-DD, found %q", value)
+	}
+	*d = Date(day)
+
+	return nil
+}
+
+// unquote возвращает строковое значение JSON или ошибку с описанием ожидаемого значения expected.
+// Значение null считается пустой строкой.
+func unquote(data []byte, expected string) (string, error) {
+	if string(data) == "null" {
+		return "", nil
+	}
+	value, err := strconv.Unquote(string(data))
+	if err != nil || !strings.HasPrefix(string(data), `"`) {
+		return "", fmt.Errorf("expected %s, found %s", expected, data)
+	}
+
+	return value, nil
+}
@@ -2,30 +2,153 @@ package entities
 
 import (
 	"os"
+	"system_prototype_for_biathlon_competitions/internal/config"
 	"time"
 )
 
 // Statistic представляет собой структуру, содержащую статистику участника соревнований по биатлону.
 type Statistic struct {
-	RequiredStart                 time.Time     // Запланированное время старта
-	ActualStart                   time.Time     // Фактическое время старта
-	ActualFinish                  time.Time     // Фактическое время финиша
-	StartPenaltyLaps              time.Time     // Вспомогательное фактическое время старта прохождения штрафные кругов
-	TotalTimeOfPenaltyLaps        time.Duration // Общее время, затраченное на штрафные круги
-	TimeOfLapsCompletion          []time.Time   // Временные отметки завершения кругов
-	CompetitorID                  string        // Уникальный идентификатор участника
-	NumberOfFiringRangeVisited    int           // Количество посещений огневых рубежей
-	NumberOfHits                  int           // Количество попаданий в мишени
-	NumberOfPenaltyLaps           int           // Количество назначенных штрафных кругов
-	NumberOfCompletionPenaltyLaps int           // Количество завершённых штрафных кругов
-	NumberOfEndedLaps             int           // Количество завершённых основных кругов
-	IsFinished                    bool          // Флаг, указывающий, завершил ли участник гонку
-	IsDisqualified                bool          // Флаг, указывающий, был ли участник дисквалифицирован
+	RequiredStart                 time.Time       // Запланированное время старта
+	ActualStart                   time.Time       // Фактическое время старта
+	ActualFinish                  time.Time       // Фактическое время финиша
+	StartPenaltyLaps              time.Time       // Вспомогательное фактическое время старта прохождения штрафные кругов
+	TotalTimeOfPenaltyLaps        time.Duration   // Общее время, затраченное на штрафные круги
+	TimeOfLapsCompletion          []time.Time     // Временные отметки завершения кругов
+	PenaltyVisits                 []*PenaltyVisit // Посещения огневых рубежей и последующие штрафные круги
+	TimeCorrection                time.Duration   // Поправка к итоговому времени (автоматические штрафы и решения жюри)
+	CompetitorID                  string          // Уникальный идентификатор участника
+	Category                      string          // Категория, указанная при регистрации (необязательно)
+	NumberOfFiringRangeVisited    int             // Количество посещений огневых рубежей
+	NumberOfHits                  int             // Количество попаданий в мишени
+	NumberOfPenaltyLaps           int             // Количество назначенных штрафных кругов
+	NumberOfCompletionPenaltyLaps int             // Количество завершённых штрафных кругов
+	NumberOfEndedLaps             int             // Количество завершённых основных кругов
+	IsFinished                    bool            // Флаг, указывающий, завершил ли участник гонку
+	IsDisqualified                bool            // Флаг, указывающий, что участник не стартовал в свой стартовый интервал
+	DisqualifiedAt                time.Time       // Время закрытия стартового интервала, в который участник не стартовал
+	IsLapped                      bool            // Флаг, указывающий, что участник снят с дистанции как обойдённый на круг
+	DisqualificationRule          string          // Пункт правил, по которому участник дисквалифицирован
+}
+
+// Итоговые статусы участника.
+const (
+	StatusFinished = "FIN" // Финишировал
+	StatusLapped   = "LAP" // Обойдён на круг в гонке преследования или масс-старте
+	StatusDNF      = "DNF" // Не финишировал
+	StatusDNS      = "DNS" // Не стартовал
+	StatusDSQ      = "DSQ" // Дисквалифицирован за нарушение правил
+)
+
+// PenaltyVisit представляет собой структуру, описывающую одно посещение огневого рубежа
+// и прохождение назначенных по его итогам штрафных кругов.
+type PenaltyVisit struct {
+	FiringRange string    // Номер огневого рубежа
+	Arrived     time.Time // Время выхода на огневой рубеж
+	Hits        []string  // Поражённые мишени (без повторов)
+	Shots       []Shot    // Выстрелы по событию 14 в порядке стрельбы (пусто, если передавалось только событие 6)
+	FirstShot   time.Time // Время первого выстрела
+	LastShot    time.Time // Время последнего выстрела
+	LeftRange   time.Time // Время ухода с огневого рубежа
+	Owed        int       // Количество назначенных штрафных кругов
+	Entry       time.Time // Время входа на штрафные круги (нулевое, если участник не заходил)
+	Exit        time.Time // Время выхода со штрафных кругов
+	Completed   int       // Количество пройденных штрафных кругов
+	Speed       float64   // Средняя скорость на штрафных кругах (м/с), 0 — если круги не пройдены
+}
+
+// Shot представляет собой выстрел участника на огневом рубеже.
+type Shot struct {
+	Time   time.Time // Время выстрела
+	Target string    // Номер мишени
+	Hit    bool      // Мишень поражена
+}
+
+// Виды исправлений жюри.
+const (
+	CorrectionTime      = "time"      // Добавление или вычитание времени
+	CorrectionDSQ       = "dsq"       // Дисквалификация за нарушение правил
+	CorrectionReinstate = "reinstate" // Восстановление участника, не стартовавшего вовремя
+	CorrectionVoid      = "void"      // Аннулирование входящего события
+	CorrectionReplace   = "replace"   // Замена входящего события
+)
+
+// Correction представляет собой исправление, внесённое жюри поверх входящих событий.
+type Correction struct {
+	Type         string `json:"type"`                   // Вид исправления
+	CompetitorID string `json:"competitorId,omitempty"` // Участник (для time, dsq, reinstate)
+	Value        string `json:"value,omitempty"`        // Поправка времени вида +00:00:10.000 (для time)
+	Rule         string `json:"rule,omitempty"`         // Пункт правил (для dsq)
+	Line         int    `json:"line,omitempty"`         // Номер строки файла событий, начиная с 1 (для void, replace)
+	Source       string `json:"source,omitempty"`       // Источник событий, к строке которого относится Line, при объединении источников (для void, replace)
+	Event        string `json:"event,omitempty"`        // Новая строка события (для replace)
+	IssuedBy     string `json:"issuedBy"`               // Кто внёс исправление
+	Reason       string `json:"reason"`                 // Причина исправления
+}
+
+// Athlete представляет собой структуру, содержащую регистрационные данные спортсмена.
+type Athlete struct {
+	CompetitorID string `json:"competitorId"` // Идентификатор участника в потоке событий
+	Bib          int    `json:"bib"`          // Стартовый номер
+	Name         string `json:"name"`         // Полное имя спортсмена
+	Nation       string `json:"nation"`       // Страна или клуб
+	Gender       string `json:"gender"`       // Пол (M или F)
+	Category     string `json:"category"`     // Возрастная категория
+}
+
+// Статусы результатов гонки.
+const (
+	ResultsUnofficial  = "Unofficial"  // Результаты сформированы, но не опубликованы
+	ResultsProvisional = "Provisional" // Результаты опубликованы, открыто окно подачи протестов
+	ResultsOfficial    = "Official"    // Результаты утверждены
+)
+
+// Решения по протестам.
+const (
+	ProtestPending  = ""         // Протест не рассмотрен
+	ProtestAccepted = "accepted" // Протест удовлетворён
+	ProtestRejected = "rejected" // Протест отклонён
+)
+
+// Protest представляет собой протест, поданный в отношении участника.
+type Protest struct {
+	CompetitorID string    `json:"competitorId"`        // Участник, в отношении которого подан протест
+	SubmittedAt  time.Time `json:"submittedAt"`         // Время подачи протеста по часам гонки
+	FiledBy      string    `json:"filedBy"`             // Кто подал протест
+	Reason       string    `json:"reason"`              // Суть протеста
+	Decision     string    `json:"decision,omitempty"`  // Решение жюри
+	DecidedBy    string    `json:"decidedBy,omitempty"` // Кто принял решение
+}
+
+// Race представляет собой структуру, содержащую сохранённые данные проведённой гонки.
+type Race struct {
+	ID          string                `json:"id"`          // Уникальный идентификатор гонки
+	CreatedAt   time.Time             `json:"createdAt"`   // Время сохранения гонки
+	Status      string                `json:"status"`      // Статус результатов
+	Version     int                   `json:"version"`     // Номер версии результатов
+	LastFinish  time.Time             `json:"lastFinish"`  // Время финиша последнего участника
+	Protests    []*Protest            `json:"protests"`    // Поданные протесты
+	Config      *config.Config        `json:"config"`      // Конфигурация, с которой проводилась гонка
+	Competitors []string              `json:"competitors"` // Идентификаторы зарегистрированных участников
+	Athletes    map[string]*Athlete   `json:"athletes"`    // Реестр спортсменов
+	Corrections []*Correction         `json:"corrections"` // Исправления жюри
+	Events      []string              `json:"events"`      // Исходные входящие события
+	Statistics  map[string]*Statistic `json:"statistics"`  // Статистика участников
+	Results     []string              `json:"results"`     // Строки итоговой таблицы
+
+	EventOrigins []string `json:"eventOrigins,omitempty"` // Источник и строка каждого события при объединении нескольких источников
 }
 
 // Files представляет собой структуру, содержащую ссылки на файлы конфигурации и событий.
 type Files struct {
-	ConfigFile *os.File
-	EventsFile *os.File
+	ConfigFile      *os.File
+	EventsFile      *os.File
+	EventSources    []*EventSource // Файлы событий нескольких устройств хронометража (вместо EventsFile)
+	AthletesFile    *os.File       // Необязательный файл реестра спортсменов (CSV или JSON)
+	CorrectionsFile *os.File       // Необязательный файл исправлений жюри (JSON)
 }
 
+// EventSource представляет собой файл событий одного устройства хронометража.
+type EventSource struct {
+	Name string   // Имя источника в ссылках на события (например, путь к файлу)
+	File *os.File // Файл событий
+}
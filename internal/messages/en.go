@@ -0,0 +1,147 @@
+package messages
+
+// english содержит сообщения на английском языке.
+var english = map[string]string{
+	EventKey("1"):  "The competitor(%[1]s) registered",
+	EventKey("2"):  "The start time for the competitor(%[1]s) was set by a draw to %[2]s",
+	EventKey("3"):  "The competitor(%[1]s) is on the start line",
+	EventKey("4"):  "The competitor(%[1]s) has started",
+	EventKey("5"):  "The competitor(%[1]s) is on the firing range(%[2]s)",
+	EventKey("6"):  "The target(%[2]s) has been hit by competitor(%[1]s)",
+	EventKey("7"):  "The competitor(%[1]s) left the firing range",
+	EventKey("8"):  "The competitor(%[1]s) entered the penalty laps",
+	EventKey("9"):  "The competitor(%[1]s) left the penalty laps",
+	EventKey("10"): "The competitor(%[1]s) ended the main lap",
+	EventKey("11"): "The competitor(%[1]s) can`t continue: %[2]s",
+	EventKey("12"): "The competitor(%[1]s) is lapped",
+	EventKey("13"): "The competitor(%[1]s) is disqualified for a rule violation: %[2]s",
+	EventKey("14"): "The competitor(%[1]s) fired at the target(%[2]s): %[3]s",
+	EventKey("32"): "The competitor(%[1]s) is disqualified",
+	EventKey("33"): "The competitor(%[1]s) has finished",
+	EventKey("34"): "The competitor(%[1]s) did not finish",
+	EventKey("35"): "The competitor(%[1]s) is out of the race as lapped",
+	EventKey("36"): "The competitor(%[1]s) is out of the race as disqualified: %[2]s",
+	EventKey("37"): "The protest window after the finish of the competitor(%[1]s) is closed",
+	EventKey("38"): "The competitor(%[1]s) is still on the course at the cutoff time",
+
+	ReportResults:        "Results: %s, version %d",
+	ReportAudit:          "Audit",
+	ReportProtests:       "Protests",
+	ReportOverall:        "Overall",
+	ReportCategory:       "Category: %s",
+	ReportGender:         "Gender: %s",
+	ReportPenaltyLoops:   "Penalty loops: %s",
+	ReportAnalysis:       "Time analysis",
+	ReportCourseTime:     "Course time",
+	ReportRangeTime:      "Range time",
+	ReportShootingTime:   "Shooting time",
+	ReportPenaltyTime:    "Penalty time",
+	ReportShooting:       "Shooting",
+	ReportShootingRhythm: "Shooting rhythm",
+	ReportCleanShooting:  "Clean shooting",
+	ReportFastestRange:   "Fastest range times",
+	ReportMissedTargets:  "Missed targets",
+	ReportFiringRanges:   "Firing ranges",
+
+	StatusLapped:       "Lapped",
+	StatusNotFinished:  "NotFinished",
+	StatusNotStarted:   "NotStarted",
+	StatusDisqualified: "Disqualified(%s)",
+
+	ResultsUnofficial:  "Unofficial",
+	ResultsProvisional: "Provisional",
+	ResultsOfficial:    "Official",
+	GroupUnassigned:    "Unassigned",
+
+	JuryViolation: "[%[1]s] %[2]s firing range(%[3]s): %[4]s",
+	JurySkipped:   "skipped %d penalty laps",
+	JuryShort:     "left penalty laps after %s, minimum for %d laps is %s, missing %d",
+	JuryNotLeft:   "entered %d penalty laps at %s, never left them",
+
+	PenaltyVisit:     "firing range(%s): %d/%d",
+	PenaltyNone:      "%s, no penalty loops",
+	PenaltySkipped:   "%s, owed %d, skipped",
+	PenaltyNotLeft:   "%s, owed %d, entered [%s], not left",
+	PenaltyCompleted: "%s, owed %d, completed %d [%s-%s] {%s,%s}",
+	PenaltyTotal:     "total: completed %d of %d %s",
+
+	AnalysisCourse:   "course",
+	AnalysisRange:    "range",
+	AnalysisShooting: "shooting",
+	AnalysisPenalty:  "penalty",
+
+	ShootingLine:            "%s, bouts %s",
+	ShootingProne:           "prone",
+	ShootingStanding:        "standing",
+	ShootingRhythm:          "firing range(%s): %s",
+	ShootingRhythmMissed:    "%s, missed %s",
+	ShootingRhythmIntervals: "%s, intervals %s, %s per shot",
+	ShootingFastest:         "%d. [%s] %s, firing range(%s)",
+	ShootingMiss:            "target %s: %d miss",
+	ShootingMisses:          "target %s: %d misses",
+	ShootingRange:           "firing range(%s): %d visits, %s",
+	ShootingRangeTime:       "%s, average range time %s",
+
+	AuditCompetitor: "competitor(%s)",
+	AuditLine:       "line(%d)",
+	AuditSourceLine: "line(%s:%d)",
+	AuditRule:       "rule %s",
+	AuditIssued:     "%s issued by %s: %s",
+	ProtestFiled:    "[%d] competitor(%s) filed by %s at %s: %s",
+	ProtestPending:  "%s - pending",
+	ProtestDecided:  "%s - %s by %s",
+
+	DiagnosticLine:       "line %d",
+	DiagnosticLineOrigin: "line %d (%s)",
+	DiagnosticOrder:      "%s: %s is %s earlier than previous event, %s",
+	DiagnosticReordered:  "reordered",
+	DiagnosticAccepted:   "accepted",
+	DiagnosticDuplicate:  "%s: %s duplicates %s",
+
+	ValidateSummary:              "%d errors, %d warnings",
+	ValidateError:                "error",
+	ValidateWarning:              "warning",
+	ValidateConfig:               "Config",
+	ValidateSyntax:               "Syntax",
+	ValidateOrder:                "Event order",
+	ValidateCompetitors:          "Competitors",
+	ValidateSequence:             "Sequence",
+	ValidateLapLen:               "lapLen: %d m is outside the usual %d-%d m",
+	ValidateCourseLapLen:         "course: lap %d: %d m is outside the usual %d-%d m",
+	ValidateDistance:             "distance: %d m over %d laps is outside the usual %d-%d m",
+	ValidatePenaltyLen:           "penaltyLen: %d m is outside the usual %d-%d m",
+	ValidateStartDelta:           "startDelta: %s is longer than %s",
+	ValidateLoopSpeed:            "penaltyLoopMaxSpeed: %g m/s is faster than %g m/s",
+	ValidateRegisteredTwice:      "competitor %s has already been registered",
+	ValidateNotRegistered:        "competitor %s is not registered",
+	ValidateFinished:             "competitor %s has already finished",
+	ValidateWithdrawn:            "competitor %s could not continue earlier",
+	ValidateDrawAfterStart:       "competitor %s got a start time after the start",
+	ValidateDrawnTwice:           "competitor %s got a start time again",
+	ValidateStartLineUndrawn:     "competitor %s is on the start line without a start time",
+	ValidateStartLineStarted:     "competitor %s is on the start line after the start",
+	ValidateStartUndrawn:         "competitor %s started without a start time",
+	ValidateStartedTwice:         "competitor %s started twice",
+	ValidateRangeBeforeStart:     "competitor %s is on the firing range before the start",
+	ValidateRangeOnRange:         "competitor %s is on the firing range without leaving it",
+	ValidateRangeInPenalty:       "competitor %s is on the firing range without leaving the penalty laps",
+	ValidateHitOffRange:          "competitor %s hit a target outside the firing range",
+	ValidateHitTwice:             "competitor %s hit target %s twice",
+	ValidateLeftRangeOffRange:    "competitor %s left the firing range without being on it",
+	ValidatePenaltyTimeMode:      "penalty laps are not allowed in penalty time mode",
+	ValidatePenaltyOnRange:       "competitor %s entered the penalty laps without leaving the firing range",
+	ValidatePenaltyTwice:         "competitor %s entered the penalty laps twice",
+	ValidatePenaltyWithoutMisses: "competitor %s entered the penalty laps without misses",
+	ValidateLeftPenaltyOutside:   "competitor %s left the penalty laps without entering them",
+	ValidateLapBeforeStart:       "competitor %s ended a lap before the start",
+	ValidateLapOnRange:           "competitor %s ended a lap on the firing range",
+	ValidateLapInPenalty:         "competitor %s ended a lap on the penalty laps",
+	ValidateLapWithoutShooting:   "competitor %s ended lap %d without shooting on firing line %d",
+	ValidateShotOffRange:         "competitor %s fired a shot outside the firing range",
+	ValidateTooManyShots:         "competitor %s fired more than %d shots on the firing range",
+	ValidateRangeTwiceOnLap:      "competitor %s is on the firing range twice on lap %d",
+	ValidateRangeOffCourse:       "competitor %s is on the firing range on lap %d without shooting by the course",
+	ValidateWrongFiringLine:      "competitor %s is on firing range %d on lap %d, expected firing line %d by the course",
+	ValidateNoStartTime:          "competitor %s has no start time",
+	ValidateNotFinished:          "competitor %s neither finished nor reported that they cannot continue",
+}
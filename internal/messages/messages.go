@@ -0,0 +1,273 @@
+package messages
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Поддерживаемые языки сообщений.
+const (
+	LocaleEnglish = "en"
+	LocaleRussian = "ru"
+)
+
+// DefaultLocale задаёт язык сообщений по умолчанию.
+const DefaultLocale = LocaleEnglish
+
+// Ключи заголовков отчётов.
+const (
+	ReportResults  = "report.results"  // Статус и версия результатов
+	ReportAudit    = "report.audit"    // Раздел исправлений жюри
+	ReportProtests = "report.protests" // Раздел протестов
+	ReportOverall  = "report.overall"  // Общая таблица
+	ReportCategory = "report.category" // Таблица категории
+	ReportGender   = "report.gender"   // Таблица пола
+
+	ReportPenaltyLoops   = "report.penaltyLoops"   // Раздел штрафных кругов участника
+	ReportAnalysis       = "report.analysis"       // Раздел составляющих времени участников
+	ReportCourseTime     = "report.courseTime"     // Рейтинг по чистому времени на трассе
+	ReportRangeTime      = "report.rangeTime"      // Рейтинг по времени на огневых рубежах
+	ReportShootingTime   = "report.shootingTime"   // Рейтинг по времени стрельбы
+	ReportPenaltyTime    = "report.penaltyTime"    // Рейтинг по штрафному времени
+	ReportShooting       = "report.shooting"       // Раздел точности стрельбы участников
+	ReportShootingRhythm = "report.shootingRhythm" // Ритм стрельбы по событиям 14
+	ReportCleanShooting  = "report.cleanShooting"  // Участники, стрелявшие без промахов
+	ReportFastestRange   = "report.fastestRange"   // Быстрейшее время на огневых рубежах
+	ReportMissedTargets  = "report.missedTargets"  // Промахи по номерам мишеней
+	ReportFiringRanges   = "report.firingRanges"   // Итоги по огневым рубежам
+)
+
+// Ключи представлений нефинишных статусов участника.
+const (
+	StatusLapped       = "status.lapped"       // Обойдён на круг
+	StatusNotFinished  = "status.notFinished"  // Не финишировал
+	StatusNotStarted   = "status.notStarted"   // Не стартовал
+	StatusDisqualified = "status.disqualified" // Дисквалифицирован за нарушение правил
+)
+
+// Ключи статусов результатов гонки и группы участников без назначения.
+const (
+	ResultsUnofficial  = "results.unofficial"  // Результаты сформированы, но не опубликованы
+	ResultsProvisional = "results.provisional" // Результаты опубликованы, протесты принимаются
+	ResultsOfficial    = "results.official"    // Результаты утверждены
+	GroupUnassigned    = "group.unassigned"    // Категория или пол участника неизвестны
+)
+
+// Ключи строк отчёта для жюри.
+const (
+	JuryViolation = "jury.violation" // Нарушение на огневом рубеже
+	JurySkipped   = "jury.skipped"   // Штрафные круги пропущены
+	JuryShort     = "jury.short"     // Штрафные круги пройдены слишком быстро
+	JuryNotLeft   = "jury.notLeft"   // Участник не ушёл со штрафных кругов
+)
+
+// Ключи строк отчёта о штрафных кругах.
+const (
+	PenaltyVisit     = "penalty.visit"     // Огневой рубеж и результат стрельбы
+	PenaltyNone      = "penalty.none"      // Штрафные круги не назначены
+	PenaltySkipped   = "penalty.skipped"   // Штрафные круги пропущены
+	PenaltyNotLeft   = "penalty.notLeft"   // Участник не ушёл со штрафных кругов
+	PenaltyCompleted = "penalty.completed" // Штрафные круги пройдены
+	PenaltyTotal     = "penalty.total"     // Итог по штрафным кругам участника
+)
+
+// Ключи составляющих времени в отчёте о составляющих времени.
+const (
+	AnalysisCourse   = "analysis.course"   // Чистое время на трассе
+	AnalysisRange    = "analysis.range"    // Время на огневых рубежах
+	AnalysisShooting = "analysis.shooting" // Время стрельбы
+	AnalysisPenalty  = "analysis.penalty"  // Время на штрафных кругах или штрафное время
+)
+
+// Ключи строк отчёта о стрельбе.
+const (
+	ShootingLine            = "shooting.line"            // Итог и результаты стрельб участника
+	ShootingProne           = "shooting.prone"           // Положение лёжа
+	ShootingStanding        = "shooting.standing"        // Положение стоя
+	ShootingRhythm          = "shooting.rhythm"          // Последовательность выстрелов на огневом рубеже
+	ShootingRhythmMissed    = "shooting.rhythmMissed"    // Непоражённые мишени
+	ShootingRhythmIntervals = "shooting.rhythmIntervals" // Интервалы между выстрелами и время на выстрел
+	ShootingFastest         = "shooting.fastest"         // Место и время посещения огневого рубежа
+	ShootingMiss            = "shooting.miss"            // Мишень с одним промахом
+	ShootingMisses          = "shooting.misses"          // Мишень с несколькими промахами
+	ShootingRange           = "shooting.range"           // Итоги огневого рубежа
+	ShootingRangeTime       = "shooting.rangeTime"       // Среднее время на огневом рубеже
+)
+
+// Ключи строк аудита и протестов.
+const (
+	AuditCompetitor = "audit.competitor" // Исправление результата участника
+	AuditLine       = "audit.line"       // Исправление строки файла событий
+	AuditSourceLine = "audit.sourceLine" // Исправление строки источника событий
+	AuditRule       = "audit.rule"       // Пункт правил дисквалификации
+	AuditIssued     = "audit.issued"     // Автор и причина исправления
+	ProtestFiled    = "protest.filed"    // Поданный протест
+	ProtestPending  = "protest.pending"  // Протест не рассмотрен
+	ProtestDecided  = "protest.decided"  // Решение по протесту
+)
+
+// Ключи записей о повторах и нарушениях порядка событий.
+const (
+	DiagnosticLine       = "diagnostic.line"       // Номер строки события
+	DiagnosticLineOrigin = "diagnostic.lineOrigin" // Номер строки события и её источник
+	DiagnosticOrder      = "diagnostic.order"      // Событие раньше предыдущего
+	DiagnosticReordered  = "diagnostic.reordered"  // Событие переставлено
+	DiagnosticAccepted   = "diagnostic.accepted"   // Событие обработано в порядке поступления
+	DiagnosticDuplicate  = "diagnostic.duplicate"  // Отброшенный повтор события
+)
+
+// Ключи результата проверки входных файлов.
+const (
+	ValidateSummary              = "validate.summary"              // Итоговая строка
+	ValidateError                = "validate.error"                // Серьёзность: ошибка
+	ValidateWarning              = "validate.warning"              // Серьёзность: предупреждение
+	ValidateConfig               = "validate.config"               // Категория конфигурации
+	ValidateSyntax               = "validate.syntax"               // Категория синтаксиса
+	ValidateOrder                = "validate.order"                // Категория порядка событий
+	ValidateCompetitors          = "validate.competitors"          // Категория участников
+	ValidateSequence             = "validate.sequence"             // Категория последовательности событий
+	ValidateLapLen               = "validate.lapLen"               // Длина круга вне правдоподобных границ
+	ValidateCourseLapLen         = "validate.courseLapLen"         // Длина круга трассы вне правдоподобных границ
+	ValidateDistance             = "validate.distance"             // Дистанция вне правдоподобных границ
+	ValidatePenaltyLen           = "validate.penaltyLen"           // Длина штрафного круга вне правдоподобных границ
+	ValidateStartDelta           = "validate.startDelta"           // Слишком длинный стартовый интервал
+	ValidateLoopSpeed            = "validate.loopSpeed"            // Слишком высокая скорость на штрафном круге
+	ValidateRegisteredTwice      = "validate.registeredTwice"      // Повторная регистрация участника
+	ValidateNotRegistered        = "validate.notRegistered"        // Участник не зарегистрирован
+	ValidateFinished             = "validate.finished"             // Событие после финиша
+	ValidateWithdrawn            = "validate.withdrawn"            // Событие после схода
+	ValidateDrawAfterStart       = "validate.drawAfterStart"       // Время старта назначено после старта
+	ValidateDrawnTwice           = "validate.drawnTwice"           // Повторное назначение времени старта
+	ValidateStartLineUndrawn     = "validate.startLineUndrawn"     // Выход на старт без времени старта
+	ValidateStartLineStarted     = "validate.startLineStarted"     // Выход на старт после старта
+	ValidateStartUndrawn         = "validate.startUndrawn"         // Старт без времени старта
+	ValidateStartedTwice         = "validate.startedTwice"         // Повторный старт
+	ValidateRangeBeforeStart     = "validate.rangeBeforeStart"     // Огневой рубеж до старта
+	ValidateRangeOnRange         = "validate.rangeOnRange"         // Повторный приход на огневой рубеж
+	ValidateRangeInPenalty       = "validate.rangeInPenalty"       // Огневой рубеж во время штрафных кругов
+	ValidateHitOffRange          = "validate.hitOffRange"          // Попадание вне огневого рубежа
+	ValidateHitTwice             = "validate.hitTwice"             // Повторное попадание в мишень
+	ValidateLeftRangeOffRange    = "validate.leftRangeOffRange"    // Уход с огневого рубежа без прихода на него
+	ValidatePenaltyTimeMode      = "validate.penaltyTimeMode"      // Штрафные круги в режиме штрафного времени
+	ValidatePenaltyOnRange       = "validate.penaltyOnRange"       // Штрафные круги до ухода с огневого рубежа
+	ValidatePenaltyTwice         = "validate.penaltyTwice"         // Повторный вход на штрафные круги
+	ValidatePenaltyWithoutMisses = "validate.penaltyWithoutMisses" // Штрафные круги без промахов
+	ValidateLeftPenaltyOutside   = "validate.leftPenaltyOutside"   // Уход со штрафных кругов без входа на них
+	ValidateLapBeforeStart       = "validate.lapBeforeStart"       // Круг до старта
+	ValidateLapOnRange           = "validate.lapOnRange"           // Круг на огневом рубеже
+	ValidateLapInPenalty         = "validate.lapInPenalty"         // Круг на штрафных кругах
+	ValidateLapWithoutShooting   = "validate.lapWithoutShooting"   // Круг без стрельбы по трассе
+	ValidateShotOffRange         = "validate.shotOffRange"         // Выстрел вне огневого рубежа
+	ValidateTooManyShots         = "validate.tooManyShots"         // Лишние выстрелы на огневом рубеже
+	ValidateRangeTwiceOnLap      = "validate.rangeTwiceOnLap"      // Два огневых рубежа на одном круге
+	ValidateRangeOffCourse       = "validate.rangeOffCourse"       // Огневой рубеж на круге без стрельбы по трассе
+	ValidateWrongFiringLine      = "validate.wrongFiringLine"      // Не тот огневой рубеж по трассе
+	ValidateNoStartTime          = "validate.noStartTime"          // Участнику не назначено время старта
+	ValidateNotFinished          = "validate.notFinished"          // Участник не финишировал и не сошёл
+)
+
+// catalogs содержит сообщения для каждого языка. Сообщения о событиях получают
+// идентификатор участника первым аргументом, а параметры события — следующими.
+var catalogs = map[string]map[string]string{
+	LocaleEnglish: english,
+	LocaleRussian: russian,
+}
+
+// Catalog представляет собой каталог сообщений журнала событий и заголовков отчётов на одном языке.
+type Catalog struct {
+	locale   string
+	messages map[string]string
+}
+
+// New возвращает каталог сообщений для языка locale.
+func New(locale string) (*Catalog, error) {
+	messages, ok := catalogs[locale]
+	if !ok {
+		return nil, fmt.Errorf("unknown locale: %q, supported: %s", locale, strings.Join(Locales(), ", "))
+	}
+
+	return &Catalog{locale: locale, messages: messages}, nil
+}
+
+// Register добавляет сообщение format с ключом key в каталог языка locale, например, для журнала
+// пользовательских событий. Регистрация выполняется при инициализации пакета, до обработки событий.
+func Register(locale, key, format string) error {
+	messages, ok := catalogs[locale]
+	if !ok {
+		return fmt.Errorf("unknown locale: %q", locale)
+	}
+	messages[key] = format
+
+	return nil
+}
+
+// Locales возвращает список поддерживаемых языков.
+func Locales() []string {
+	locales := make([]string, 0, len(catalogs))
+	for locale := range catalogs {
+		locales = append(locales, locale)
+	}
+	slices.Sort(locales)
+
+	return locales
+}
+
+// EventKey возвращает ключ сообщения журнала для события с идентификатором eventID.
+func EventKey(eventID string) string {
+	return "event." + eventID
+}
+
+// Locale возвращает язык каталога.
+func (c *Catalog) Locale() string {
+	if c == nil {
+		return DefaultLocale
+	}
+
+	return c.locale
+}
+
+// Has сообщает, есть ли в каталоге сообщение с ключом key.
+func (c *Catalog) Has(key string) bool {
+	_, ok := c.lookup(key)
+	return ok
+}
+
+// Keys возвращает ключи всех сообщений каталога по алфавиту.
+func (c *Catalog) Keys() []string {
+	messages := c.entries()
+	keys := make([]string, 0, len(messages))
+	for key := range messages {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	return keys
+}
+
+// Format возвращает сообщение с ключом key, подставляя в него args.
+// Пустой каталог использует сообщения языка по умолчанию, а отсутствующее сообщение
+// заменяется ключом и аргументами, чтобы не терять данные в журнале.
+func (c *Catalog) Format(key string, args ...any) string {
+	message, ok := c.lookup(key)
+	if !ok {
+		return strings.TrimSpace(fmt.Sprintln(append([]any{key}, args...)...))
+	}
+
+	return fmt.Sprintf(message, args...)
+}
+
+// lookup ищет сообщение с ключом key.
+func (c *Catalog) lookup(key string) (string, bool) {
+	message, ok := c.entries()[key]
+	return message, ok
+}
+
+// entries возвращает сообщения каталога; пустой каталог использует язык по умолчанию.
+func (c *Catalog) entries() map[string]string {
+	if c == nil {
+		return catalogs[DefaultLocale]
+	}
+
+	return c.messages
+}
@@ -0,0 +1,43 @@
+package messages_test
+
+import (
+	"system_prototype_for_biathlon_competitions/internal/messages"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestCatalog тестирует выбор каталога и форматирование сообщений.
+func TestCatalog(t *testing.T) {
+	require.Equal(t, []string{messages.LocaleEnglish, messages.LocaleRussian}, messages.Locales())
+
+	english, err := messages.New(messages.LocaleEnglish)
+	require.NoError(t, err)
+	require.Equal(t, "The target(3) has been hit by competitor(1)", english.Format(messages.EventKey("6"), "1", "3"))
+
+	russian, err := messages.New(messages.LocaleRussian)
+	require.NoError(t, err)
+	require.Equal(t, "Результаты: Официальные, версия 3", russian.Format(messages.ReportResults, russian.Format(messages.ResultsOfficial), 3))
+
+	var defaultCatalog *messages.Catalog
+	require.Equal(t, "Overall", defaultCatalog.Format(messages.ReportOverall))
+	require.Equal(t, "event.99 1", english.Format(messages.EventKey("99"), "1"))
+
+	_, err = messages.New("de")
+	require.Error(t, err)
+}
+
+// TestCatalogsHaveSameKeys тестирует, что каталоги всех языков содержат одинаковые сообщения.
+func TestCatalogsHaveSameKeys(t *testing.T) {
+	english, err := messages.New(messages.LocaleEnglish)
+	require.NoError(t, err)
+
+	for _, locale := range messages.Locales() {
+		catalog, err := messages.New(locale)
+		require.NoError(t, err)
+		for _, key := range english.Keys() {
+			require.True(t, catalog.Has(key), "locale %s has no message %s", locale, key)
+		}
+		require.Len(t, catalog.Keys(), len(english.Keys()), "locale %s has extra messages", locale)
+	}
+}
@@ -0,0 +1,147 @@
+package messages
+
+// russian содержит сообщения на русском языке.
+var russian = map[string]string{
+	EventKey("1"):  "Участник(%[1]s) зарегистрирован",
+	EventKey("2"):  "Время старта участника(%[1]s) определено жеребьёвкой: %[2]s",
+	EventKey("3"):  "Участник(%[1]s) на стартовой линии",
+	EventKey("4"):  "Участник(%[1]s) стартовал",
+	EventKey("5"):  "Участник(%[1]s) на огневом рубеже(%[2]s)",
+	EventKey("6"):  "Мишень(%[2]s) поражена участником(%[1]s)",
+	EventKey("7"):  "Участник(%[1]s) покинул огневой рубеж",
+	EventKey("8"):  "Участник(%[1]s) вышел на штрафные круги",
+	EventKey("9"):  "Участник(%[1]s) покинул штрафные круги",
+	EventKey("10"): "Участник(%[1]s) завершил основной круг",
+	EventKey("11"): "Участник(%[1]s) не может продолжить: %[2]s",
+	EventKey("12"): "Участник(%[1]s) обойдён на круг",
+	EventKey("13"): "Участник(%[1]s) дисквалифицирован за нарушение правил: %[2]s",
+	EventKey("14"): "Участник(%[1]s) выстрелил по мишени(%[2]s): %[3]s",
+	EventKey("32"): "Участник(%[1]s) дисквалифицирован",
+	EventKey("33"): "Участник(%[1]s) финишировал",
+	EventKey("34"): "Участник(%[1]s) не финишировал",
+	EventKey("35"): "Участник(%[1]s) снят с дистанции как обойдённый на круг",
+	EventKey("36"): "Участник(%[1]s) снят с дистанции как дисквалифицированный: %[2]s",
+	EventKey("37"): "Окно подачи протестов после финиша участника(%[1]s) закрыто",
+	EventKey("38"): "Участник(%[1]s) находится на трассе по истечении лимита времени",
+
+	ReportResults:        "Результаты: %s, версия %d",
+	ReportAudit:          "Аудит",
+	ReportProtests:       "Протесты",
+	ReportOverall:        "Общий зачёт",
+	ReportCategory:       "Категория: %s",
+	ReportGender:         "Пол: %s",
+	ReportPenaltyLoops:   "Штрафные круги: %s",
+	ReportAnalysis:       "Анализ времени",
+	ReportCourseTime:     "Время на трассе",
+	ReportRangeTime:      "Время на огневых рубежах",
+	ReportShootingTime:   "Время стрельбы",
+	ReportPenaltyTime:    "Штрафное время",
+	ReportShooting:       "Стрельба",
+	ReportShootingRhythm: "Ритм стрельбы",
+	ReportCleanShooting:  "Стрельба без промахов",
+	ReportFastestRange:   "Быстрейшее время на огневом рубеже",
+	ReportMissedTargets:  "Промахи по мишеням",
+	ReportFiringRanges:   "Огневые рубежи",
+
+	StatusLapped:       "Обойдён",
+	StatusNotFinished:  "НеФинишировал",
+	StatusNotStarted:   "НеСтартовал",
+	StatusDisqualified: "Дисквалифицирован(%s)",
+
+	ResultsUnofficial:  "Неофициальные",
+	ResultsProvisional: "Предварительные",
+	ResultsOfficial:    "Официальные",
+	GroupUnassigned:    "Не указано",
+
+	JuryViolation: "[%[1]s] %[2]s огневой рубеж(%[3]s): %[4]s",
+	JurySkipped:   "пропущено штрафных кругов: %d",
+	JuryShort:     "ушёл со штрафных кругов через %s, минимум для %d кругов %s, не хватает %d",
+	JuryNotLeft:   "вошёл на штрафные круги (%d) в %s и не ушёл с них",
+
+	PenaltyVisit:     "огневой рубеж(%s): %d/%d",
+	PenaltyNone:      "%s, без штрафных кругов",
+	PenaltySkipped:   "%s, назначено %d, пропущены",
+	PenaltyNotLeft:   "%s, назначено %d, вход [%s], выход не отмечен",
+	PenaltyCompleted: "%s, назначено %d, пройдено %d [%s-%s] {%s,%s}",
+	PenaltyTotal:     "итого: пройдено %d из %d %s",
+
+	AnalysisCourse:   "трасса",
+	AnalysisRange:    "рубежи",
+	AnalysisShooting: "стрельба",
+	AnalysisPenalty:  "штраф",
+
+	ShootingLine:            "%s, стрельбы %s",
+	ShootingProne:           "лёжа",
+	ShootingStanding:        "стоя",
+	ShootingRhythm:          "огневой рубеж(%s): %s",
+	ShootingRhythmMissed:    "%s, промахи %s",
+	ShootingRhythmIntervals: "%s, интервалы %s, %s на выстрел",
+	ShootingFastest:         "%d. [%s] %s, огневой рубеж(%s)",
+	ShootingMiss:            "мишень %s: промахов: %d",
+	ShootingMisses:          "мишень %s: промахов: %d",
+	ShootingRange:           "огневой рубеж(%s): посещений: %d, %s",
+	ShootingRangeTime:       "%s, среднее время на рубеже %s",
+
+	AuditCompetitor: "участник(%s)",
+	AuditLine:       "строка(%d)",
+	AuditSourceLine: "строка(%s:%d)",
+	AuditRule:       "пункт %s",
+	AuditIssued:     "%s, выдал %s: %s",
+	ProtestFiled:    "[%d] участник(%s), подал %s в %s: %s",
+	ProtestPending:  "%s - не рассмотрен",
+	ProtestDecided:  "%s - %s, решение принял %s",
+
+	DiagnosticLine:       "строка %d",
+	DiagnosticLineOrigin: "строка %d (%s)",
+	DiagnosticOrder:      "%s: %s на %s раньше предыдущего события, %s",
+	DiagnosticReordered:  "переставлено",
+	DiagnosticAccepted:   "принято",
+	DiagnosticDuplicate:  "%s: %s повторяет %s",
+
+	ValidateSummary:              "ошибок: %d, предупреждений: %d",
+	ValidateError:                "ошибка",
+	ValidateWarning:              "предупреждение",
+	ValidateConfig:               "Конфигурация",
+	ValidateSyntax:               "Синтаксис",
+	ValidateOrder:                "Порядок событий",
+	ValidateCompetitors:          "Участники",
+	ValidateSequence:             "Последовательность",
+	ValidateLapLen:               "lapLen: %d м вне обычных %d-%d м",
+	ValidateCourseLapLen:         "course: круг %d: %d м вне обычных %d-%d м",
+	ValidateDistance:             "distance: %d м за %d кругов вне обычных %d-%d м",
+	ValidatePenaltyLen:           "penaltyLen: %d м вне обычных %d-%d м",
+	ValidateStartDelta:           "startDelta: %s дольше %s",
+	ValidateLoopSpeed:            "penaltyLoopMaxSpeed: %g м/с быстрее %g м/с",
+	ValidateRegisteredTwice:      "участник %s уже зарегистрирован",
+	ValidateNotRegistered:        "участник %s не зарегистрирован",
+	ValidateFinished:             "участник %s уже финишировал",
+	ValidateWithdrawn:            "участник %s ранее не смог продолжить",
+	ValidateDrawAfterStart:       "участник %s получил время старта после старта",
+	ValidateDrawnTwice:           "участник %s повторно получил время старта",
+	ValidateStartLineUndrawn:     "участник %s на стартовой линии без времени старта",
+	ValidateStartLineStarted:     "участник %s на стартовой линии после старта",
+	ValidateStartUndrawn:         "участник %s стартовал без времени старта",
+	ValidateStartedTwice:         "участник %s стартовал дважды",
+	ValidateRangeBeforeStart:     "участник %s на огневом рубеже до старта",
+	ValidateRangeOnRange:         "участник %s на огневом рубеже, не покинув его",
+	ValidateRangeInPenalty:       "участник %s на огневом рубеже, не покинув штрафные круги",
+	ValidateHitOffRange:          "участник %s поразил мишень вне огневого рубежа",
+	ValidateHitTwice:             "участник %s дважды поразил мишень %s",
+	ValidateLeftRangeOffRange:    "участник %s покинул огневой рубеж, не выйдя на него",
+	ValidatePenaltyTimeMode:      "штрафные круги недопустимы в режиме штрафного времени",
+	ValidatePenaltyOnRange:       "участник %s вошёл на штрафные круги, не покинув огневой рубеж",
+	ValidatePenaltyTwice:         "участник %s дважды вошёл на штрафные круги",
+	ValidatePenaltyWithoutMisses: "участник %s вошёл на штрафные круги без промахов",
+	ValidateLeftPenaltyOutside:   "участник %s покинул штрафные круги, не войдя на них",
+	ValidateLapBeforeStart:       "участник %s завершил круг до старта",
+	ValidateLapOnRange:           "участник %s завершил круг на огневом рубеже",
+	ValidateLapInPenalty:         "участник %s завершил круг на штрафных кругах",
+	ValidateLapWithoutShooting:   "участник %s завершил круг %d без стрельбы на огневом рубеже %d",
+	ValidateShotOffRange:         "участник %s выстрелил вне огневого рубежа",
+	ValidateTooManyShots:         "участник %s сделал на огневом рубеже больше %d выстрелов",
+	ValidateRangeTwiceOnLap:      "участник %s дважды на огневом рубеже на круге %d",
+	ValidateRangeOffCourse:       "участник %s на огневом рубеже на круге %d без стрельбы по описанию трассы",
+	ValidateWrongFiringLine:      "участник %s на огневом рубеже %d на круге %d, по описанию трассы ожидался рубеж %d",
+	ValidateNoStartTime:          "у участника %s нет времени старта",
+	ValidateNotFinished:          "участник %s не финишировал и не сообщил, что не может продолжить",
+}
@@ -0,0 +1,257 @@
+package services
+
+import (
+	"bufio"
+	"cmp"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"system_prototype_for_biathlon_competitions/internal/config"
+	"system_prototype_for_biathlon_competitions/internal/entities"
+	"system_prototype_for_biathlon_competitions/internal/messages"
+	"time"
+)
+
+// Format of the analysis report:
+// - a line per started competitor in the order of the final report with every time component
+//   and the rank of the competitor in it ("-" for competitors who did not finish)
+// - a ranking of the finished competitors for every component; equal times share a place
+// Course time is the time from the start to the finish (or the last ended lap) without range and penalty loops time.
+// Shooting time is the time from the first to the last event 14 shot of every visit; it is shown as "-" and
+// not ranked for competitors without visits or with visits reported without event 14, and the ranking
+// is omitted when no competitor has it.
+//
+// Example:
+// == Time analysis ==
+// 2 #102 Anna Sokolova (RUS): course 00:22:18.356 (1), range 00:00:30.000 (2), shooting 00:00:05.000 (1), penalty 00:01:40.000 (1)
+//
+// == Course time ==
+// 1. [00:22:18.356] 2 #102 Anna Sokolova (RUS)
+
+// Составляющие времени участника.
+const (
+	ComponentCourse   = "course"   // Чистое время на трассе
+	ComponentRange    = "range"    // Время на огневых рубежах
+	ComponentShooting = "shooting" // Время стрельбы
+	ComponentPenalty  = "penalty"  // Время на штрафных кругах или штрафное время
+)
+
+// TimeComponents перечисляет составляющие времени в порядке вывода в отчёте.
+var TimeComponents = []string{ComponentCourse, ComponentRange, ComponentShooting, ComponentPenalty}
+
+// componentNames сопоставляет составляющим времени ключи их названий в строках участников.
+var componentNames = map[string]string{
+	ComponentCourse:   messages.AnalysisCourse,
+	ComponentRange:    messages.AnalysisRange,
+	ComponentShooting: messages.AnalysisShooting,
+	ComponentPenalty:  messages.AnalysisPenalty,
+}
+
+// componentTitles сопоставляет составляющим времени заголовки разделов рейтинга.
+var componentTitles = map[string]string{
+	ComponentCourse:   messages.ReportCourseTime,
+	ComponentRange:    messages.ReportRangeTime,
+	ComponentShooting: messages.ReportShootingTime,
+	ComponentPenalty:  messages.ReportPenaltyTime,
+}
+
+// TimeAnalysis представляет собой разложение времени участника на составляющие.
+type TimeAnalysis struct {
+	CompetitorID string        // Идентификатор участника
+	Elapsed      time.Duration // Время от старта до финиша или последнего завершённого круга
+	Course       time.Duration // Чистое время на трассе без огневых рубежей и штрафных кругов
+	Range        time.Duration // Время на огневых рубежах (от события 5 до события 7)
+	Shooting     time.Duration // Время стрельбы (от первого до последнего выстрела события 14 на каждом рубеже)
+	Penalty      time.Duration // Время на штрафных кругах или, в режиме штрафного времени, начисленный штраф
+	Finished     bool          // Участник финишировал, и его составляющие участвуют в рейтингах
+	ShotsTimed   bool          // Участник посещал огневые рубежи, и выстрелы каждого посещения переданы событиями 14
+}
+
+// Has сообщает, известна ли составляющая времени component: время стрельбы известно,
+// только если выстрелы переданы событиями 14.
+func (a *TimeAnalysis) Has(component string) bool {
+	return component != ComponentShooting || a.ShotsTimed
+}
+
+// Component возвращает составляющую времени по её названию.
+func (a *TimeAnalysis) Component(component string) time.Duration {
+	switch component {
+	case ComponentCourse:
+		return a.Course
+	case ComponentRange:
+		return a.Range
+	case ComponentShooting:
+		return a.Shooting
+	case ComponentPenalty:
+		return a.Penalty
+	default:
+		return 0
+	}
+}
+
+// AnalyzeTimes раскладывает время участника на составляющие по отметкам времени его статистики.
+// Учитываются посещения огневых рубежей и штрафных кругов, завершённые до финиша
+// (или до последнего завершённого круга); время на штрафных кругах без назначенных кругов
+// не считается штрафным, как и в итогах участника. Время стрельбы считается по выстрелам событий 14
+// и неизвестно без посещений огневых рубежей или если хотя бы одно посещение передано без них. Для не стартовавшего участника возвращается nil.
+func AnalyzeTimes(statistic *entities.Statistic, config *config.Config) *TimeAnalysis {
+	if statistic.ActualStart.IsZero() {
+		return nil
+	}
+
+	analysis := &TimeAnalysis{
+		CompetitorID: statistic.CompetitorID,
+		Finished:     GetResultStatus(statistic) == entities.StatusFinished,
+	}
+	end := statistic.ActualFinish
+	if !statistic.IsFinished {
+		end = time.Time{}
+		if laps := len(statistic.TimeOfLapsCompletion); laps > 0 {
+			end = statistic.TimeOfLapsCompletion[laps-1]
+		}
+	}
+	if end.IsZero() {
+		return analysis
+	}
+	analysis.Elapsed = end.Sub(statistic.ActualStart)
+
+	var loops time.Duration
+	timed, untimed := false, false
+	for _, visit := range statistic.PenaltyVisits {
+		if !visit.Arrived.IsZero() && !visit.LeftRange.IsZero() && !visit.LeftRange.After(end) {
+			analysis.Range += visit.LeftRange.Sub(visit.Arrived)
+			if shots := len(visit.Shots); shots > 0 {
+				analysis.Shooting += visit.Shots[shots-1].Time.Sub(visit.Shots[0].Time)
+				timed = true
+			} else {
+				untimed = true
+			}
+		}
+		if duration, ok := penaltyLoopTime(visit); ok && !visit.Exit.After(end) {
+			loops += duration
+		}
+	}
+	analysis.ShotsTimed = timed && !untimed
+	analysis.Course = analysis.Elapsed - analysis.Range - loops
+	analysis.Penalty = loops
+	if IsPenaltyTimeMode(config) {
+		analysis.Penalty = GetPenaltyTime(statistic, config)
+	}
+
+	return analysis
+}
+
+// RankTimeAnalyses возвращает финишировавших участников с известной составляющей component по её возрастанию.
+func RankTimeAnalyses(analyses []*TimeAnalysis, component string) []*TimeAnalysis {
+	ranked := make([]*TimeAnalysis, 0, len(analyses))
+	for _, analysis := range analyses {
+		if analysis.Finished && analysis.Has(component) {
+			ranked = append(ranked, analysis)
+		}
+	}
+	slices.SortFunc(ranked, func(a, b *TimeAnalysis) int {
+		if c := cmp.Compare(a.Component(component), b.Component(component)); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.CompetitorID, b.CompetitorID)
+	})
+
+	return ranked
+}
+
+// sharedPlaces возвращает места n отсортированных значений value: равные соседние значения делят место.
+func sharedPlaces(n int, value func(i int) time.Duration) []int {
+	places := make([]int, n)
+	for i := range places {
+		places[i] = i + 1
+		if i > 0 && value(i) == value(i-1) {
+			places[i] = places[i-1]
+		}
+	}
+
+	return places
+}
+
+// AnalyzeStatistics раскладывает время стартовавших участников на составляющие в порядке итоговой таблицы.
+func (s *ReportService) AnalyzeStatistics() []*TimeAnalysis {
+	var analyses []*TimeAnalysis
+	for _, statistic := range s.SortStatistics() {
+		if analysis := AnalyzeTimes(statistic, s.Config); analysis != nil {
+			analyses = append(analyses, analysis)
+		}
+	}
+
+	return analyses
+}
+
+// MakeAnalysisReport создает отчёт о составляющих времени участников и записывает его в файл 'report_analysis'.
+func (s *ReportService) MakeAnalysisReport() error {
+	reportFile, err := os.Create("report_analysis")
+	if err != nil {
+		return fmt.Errorf("failed to create analysis report file: %w", err)
+	}
+	defer reportFile.Close()
+
+	return s.WriteAnalysisReport(reportFile)
+}
+
+// WriteAnalysisReport записывает в w составляющие времени каждого стартовавшего участника
+// с местами в рейтингах (при равном времени места совпадают), а затем рейтинг финишировавших участников по каждой составляющей.
+func (s *ReportService) WriteAnalysisReport(w io.Writer) error {
+	analyses := s.AnalyzeStatistics()
+	rankings := make(map[string][]*TimeAnalysis, len(TimeComponents))
+	ranks := make(map[string]map[string]int, len(TimeComponents))
+	for _, component := range TimeComponents {
+		rankings[component] = RankTimeAnalyses(analyses, component)
+		ranks[component] = make(map[string]int, len(rankings[component]))
+		places := sharedPlaces(len(rankings[component]), func(i int) time.Duration { return rankings[component][i].Component(component) })
+		for i, analysis := range rankings[component] {
+			ranks[component][analysis.CompetitorID] = places[i]
+		}
+	}
+
+	writer := bufio.NewWriter(w)
+	lines := make([]string, 0, len(analyses))
+	for _, analysis := range analyses {
+		line := GetCompetitorName(analysis.CompetitorID, s.Athletes) + ":"
+		for i, component := range TimeComponents {
+			rank := "-"
+			if place, ok := ranks[component][analysis.CompetitorID]; ok {
+				rank = strconv.Itoa(place)
+			}
+			if i > 0 {
+				line += ","
+			}
+			duration := "-"
+			if analysis.Has(component) {
+				duration = formatDuration(analysis.Component(component), s.Config)
+			}
+			line += fmt.Sprintf(" %s %s (%s)", s.Messages.Format(componentNames[component]), duration, rank)
+		}
+		lines = append(lines, line)
+	}
+	if err := writeSection(writer, s.Messages.Format(messages.ReportAnalysis), lines); err != nil {
+		return err
+	}
+
+	for _, component := range TimeComponents {
+		if component == ComponentShooting && !slices.ContainsFunc(analyses, func(analysis *TimeAnalysis) bool { return analysis.ShotsTimed }) {
+			continue
+		}
+		lines := make([]string, 0, len(rankings[component]))
+		for _, analysis := range rankings[component] {
+			lines = append(lines, fmt.Sprintf("%d. [%s] %s", ranks[component][analysis.CompetitorID], formatDuration(analysis.Component(component), s.Config), GetCompetitorName(analysis.CompetitorID, s.Athletes)))
+		}
+		if err := writeSection(writer, s.Messages.Format(componentTitles[component]), lines); err != nil {
+			return err
+		}
+	}
+
+	if err := writer.Flush(); err != nil {
+		return fmt.Errorf("failed to flush analysis report: %w", err)
+	}
+
+	return nil
+}
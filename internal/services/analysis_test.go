@@ -0,0 +1,131 @@
+package services_test
+
+import (
+	"strings"
+	"system_prototype_for_biathlon_competitions/internal/config"
+	"system_prototype_for_biathlon_competitions/internal/entities"
+	"system_prototype_for_biathlon_competitions/internal/messages"
+	"system_prototype_for_biathlon_competitions/internal/services"
+	"system_prototype_for_biathlon_competitions/internal/storage"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestAnalyzeTimes тестирует разложение времени участников на составляющие и рейтинги по ним.
+func TestAnalyzeTimes(t *testing.T) {
+	cfg := &config.Config{Laps: 2, LapLen: 3000, PenaltyLen: 150, StartDelta: config.Duration(90 * time.Second)}
+	events := "[09:00:00.000] 1 1\n" +
+		"[09:00:00.000] 1 2\n" +
+		"[09:00:00.000] 1 3\n" +
+		"[09:01:00.000] 2 1 10:00:00.000\n" +
+		"[09:01:00.000] 2 2 10:00:00.000\n" +
+		"[09:01:00.000] 2 3 10:01:30.000\n" +
+		"[10:00:00.000] 4 1\n" +
+		"[10:00:01.000] 4 2\n" +
+		"[10:01:30.000] 4 3\n" +
+		"[10:05:00.000] 5 1 1\n" +
+		"[10:05:10.000] 6 1 1\n" +
+		"[10:05:20.000] 6 1 2\n" +
+		"[10:05:30.000] 7 1\n" +
+		"[10:05:40.000] 8 1\n" +
+		"[10:06:00.000] 5 2 1\n" +
+		"[10:06:05.000] 6 2 1\n" +
+		"[10:06:06.000] 6 2 2\n" +
+		"[10:06:07.000] 6 2 3\n" +
+		"[10:06:08.000] 6 2 4\n" +
+		"[10:06:09.000] 6 2 5\n" +
+		"[10:06:20.000] 7 2\n" +
+		"[10:06:30.000] 8 2\n" +
+		"[10:07:00.000] 9 2\n" +
+		"[10:07:10.000] 9 1\n" +
+		"[10:10:00.000] 10 1\n" +
+		"[10:10:00.000] 10 2\n" +
+		"[10:12:00.000] 10 3\n" +
+		"[10:20:00.000] 10 1\n" +
+		"[10:21:00.000] 10 2\n" +
+		"[10:22:00.000] 11 3 Broken ski\n"
+	service := services.NewParseService(&entities.Files{EventsFile: openTempFile(t, "events", events)})
+	statistics, err := service.ParseEvents(cfg)
+	require.NoError(t, err)
+
+	first := services.AnalyzeTimes(statistics.GetStatistic("1"), cfg)
+	require.Equal(t, &services.TimeAnalysis{
+		CompetitorID: "1",
+		Elapsed:      20 * time.Minute,
+		Course:       20*time.Minute - 30*time.Second - 90*time.Second,
+		Range:        30 * time.Second,
+		Penalty:      90 * time.Second,
+		Finished:     true,
+	}, first)
+	require.False(t, first.Has(services.ComponentShooting))
+
+	// Штрафной круг без промахов не считается штрафным временем ни в анализе, ни в итогах участника.
+	second := services.AnalyzeTimes(statistics.GetStatistic("2"), cfg)
+	require.Zero(t, second.Penalty)
+	require.Zero(t, statistics.GetStatistic("2").TotalTimeOfPenaltyLaps)
+	require.Equal(t, 20*time.Minute+59*time.Second-20*time.Second, second.Course)
+
+	third := services.AnalyzeTimes(statistics.GetStatistic("3"), cfg)
+	require.False(t, third.Finished)
+	require.Equal(t, 10*time.Minute+30*time.Second, third.Course)
+
+	analyses := []*services.TimeAnalysis{first, second, third}
+	ranked := services.RankTimeAnalyses(analyses, services.ComponentPenalty)
+	require.Len(t, ranked, 2)
+	require.Equal(t, "2", ranked[0].CompetitorID)
+	require.Equal(t, "1", ranked[1].CompetitorID)
+
+	cfg.PenaltyMode = config.PenaltyModeTime
+	cfg.PenaltyTime = config.Duration(time.Minute)
+	require.Equal(t, 3*time.Minute, services.AnalyzeTimes(statistics.GetStatistic("1"), cfg).Penalty)
+
+	require.Nil(t, services.AnalyzeTimes(&entities.Statistic{CompetitorID: "4"}, cfg))
+}
+
+// TestWriteAnalysisReport тестирует отчёт о составляющих времени участников.
+func TestWriteAnalysisReport(t *testing.T) {
+	start := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	statistics := storage.MemoryStatistics{
+		"1": {
+			CompetitorID: "1", IsFinished: true, RequiredStart: start, ActualStart: start, ActualFinish: start.Add(20 * time.Minute),
+			PenaltyVisits: []*entities.PenaltyVisit{{
+				Arrived: start.Add(5 * time.Minute), FirstShot: start.Add(5*time.Minute + 10*time.Second),
+				LastShot: start.Add(5*time.Minute + 30*time.Second), LeftRange: start.Add(5*time.Minute + 40*time.Second),
+				Shots: []entities.Shot{
+					{Time: start.Add(5*time.Minute + 10*time.Second), Target: "1", Hit: true},
+					{Time: start.Add(5*time.Minute + 30*time.Second), Target: "2", Hit: true},
+				},
+			}},
+		},
+		"2": {CompetitorID: "2", IsFinished: true, RequiredStart: start, ActualStart: start, ActualFinish: start.Add(19 * time.Minute)},
+		"3": {CompetitorID: "3", RequiredStart: start, ActualStart: start, TimeOfLapsCompletion: []time.Time{start.Add(9 * time.Minute)}},
+		"4": {CompetitorID: "4", IsDisqualified: true},
+	}
+
+	var output strings.Builder
+	reportService := services.NewReportService(statistics, &config.Config{Laps: 2}, nil)
+	require.NoError(t, reportService.WriteAnalysisReport(&output))
+	require.Equal(t, "== Time analysis ==\n"+
+		"2: course 00:19:00.000 (1), range 00:00:00.000 (1), shooting - (-), penalty 00:00:00.000 (1)\n"+
+		"1: course 00:19:20.000 (2), range 00:00:40.000 (2), shooting 00:00:20.000 (1), penalty 00:00:00.000 (1)\n"+
+		"3: course 00:09:00.000 (-), range 00:00:00.000 (-), shooting - (-), penalty 00:00:00.000 (-)\n\n"+
+		"== Course time ==\n1. [00:19:00.000] 2\n2. [00:19:20.000] 1\n\n"+
+		"== Range time ==\n1. [00:00:00.000] 2\n2. [00:00:40.000] 1\n\n"+
+		"== Shooting time ==\n1. [00:00:20.000] 1\n\n"+
+		"== Penalty time ==\n1. [00:00:00.000] 1\n1. [00:00:00.000] 2\n\n", output.String())
+
+	statistics.GetStatistic("1").PenaltyVisits[0].Shots = nil
+	output.Reset()
+	require.NoError(t, reportService.WriteAnalysisReport(&output))
+	require.Contains(t, output.String(), "1: course 00:19:20.000 (2), range 00:00:40.000 (2), shooting - (-), penalty 00:00:00.000 (1)\n")
+	require.NotContains(t, output.String(), "== Shooting time ==")
+
+	catalog, err := messages.New(messages.LocaleRussian)
+	require.NoError(t, err)
+	reportService.Messages = catalog
+	output.Reset()
+	require.NoError(t, reportService.WriteAnalysisReport(&output))
+	require.Contains(t, output.String(), "1: трасса 00:19:20.000 (2), рубежи 00:00:40.000 (2), стрельба - (-), штраф 00:00:00.000 (1)\n")
+}
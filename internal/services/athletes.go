@@ -0,0 +1,168 @@
+package services
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"system_prototype_for_biathlon_competitions/internal/entities"
+	"system_prototype_for_biathlon_competitions/internal/storage"
+)
+
+// Athletes file format:
+//
+// CSV (the header row is required):
+// competitorId,bib,name,nation,gender,category
+// 1,101,Ivan Petrov,RUS,M,Senior
+//
+// JSON:
+// [{"competitorId": "1", "bib": 101, "name": "Ivan Petrov", "nation": "RUS", "gender": "M", "category": "Senior"}]
+
+// athletesCSVHeader содержит ожидаемые столбцы CSV-файла реестра спортсменов.
+var athletesCSVHeader = []string{"competitorId", "bib", "name", "nation", "gender", "category"}
+
+// ParseAthletes считывает и парсит файл реестра спортсменов в формате CSV или JSON.
+// Формат определяется по расширению файла. Если файл не задан, возвращается пустой реестр.
+func (s *ParseService) ParseAthletes() (map[string]*entities.Athlete, error) {
+	if s.files.AthletesFile == nil {
+		return map[string]*entities.Athlete{}, nil
+	}
+
+	var (
+		list []*entities.Athlete
+		err  error
+	)
+	rd := bufio.NewReader(s.files.AthletesFile)
+	switch strings.ToLower(filepath.Ext(s.files.AthletesFile.Name())) {
+	case ".csv":
+		list, err = decodeAthletesCSV(rd)
+	case ".json":
+		list, err = decodeAthletesJSON(rd)
+	default:
+		return nil, fmt.Errorf("unsupported athletes file format: %s", s.files.AthletesFile.Name())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return buildAthletesRegistry(list)
+}
+
+// decodeAthletesJSON декодирует список спортсменов из JSON-массива.
+func decodeAthletesJSON(r io.Reader) ([]*entities.Athlete, error) {
+	var list []*entities.Athlete
+	if err := json.NewDecoder(r).Decode(&list); err != nil {
+		return nil, fmt.Errorf("failed to decode json: %w", err)
+	}
+
+	return list, nil
+}
+
+// decodeAthletesCSV декодирует список спортсменов из CSV с обязательной строкой заголовка.
+func decodeAthletesCSV(r io.Reader) ([]*entities.Athlete, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = len(athletesCSVHeader)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read csv header: %w", err)
+	}
+	if !slices.Equal(header, athletesCSVHeader) {
+		return nil, fmt.Errorf("invalid csv header: expected %s", strings.Join(athletesCSVHeader, ","))
+	}
+
+	var list []*entities.Athlete
+	for {
+		record, err := reader.Read()
+		if err != nil {
+			if err == io.EOF {
+				break
+			}
+			return nil, fmt.Errorf("failed to read csv record: %w", err)
+		}
+
+		bib, err := strconv.Atoi(record[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid bib for competitor %s: %w", record[0], err)
+		}
+		list = append(list, &entities.Athlete{
+			CompetitorID: record[0],
+			Bib:          bib,
+			Name:         record[2],
+			Nation:       record[3],
+			Gender:       record[4],
+			Category:     record[5],
+		})
+	}
+
+	return list, nil
+}
+
+// buildAthletesRegistry проверяет записи спортсменов и строит реестр по идентификатору участника.
+func buildAthletesRegistry(list []*entities.Athlete) (map[string]*entities.Athlete, error) {
+	athletes := make(map[string]*entities.Athlete, len(list))
+	bibs := make(map[int]string, len(list))
+	for _, athlete := range list {
+		if athlete.CompetitorID == "" {
+			return nil, fmt.Errorf("invalid athletes registry: empty competitor id")
+		}
+		if athlete.Name == "" {
+			return nil, fmt.Errorf("invalid athletes registry: empty name for competitor %s", athlete.CompetitorID)
+		}
+		if athlete.Gender != "" && athlete.Gender != "M" && athlete.Gender != "F" {
+			return nil, fmt.Errorf("invalid athletes registry: unknown gender %q for competitor %s", athlete.Gender, athlete.CompetitorID)
+		}
+		if athletes[athlete.CompetitorID] != nil {
+			return nil, fmt.Errorf("invalid athletes registry: duplicate competitor %s", athlete.CompetitorID)
+		}
+		if owner, ok := bibs[athlete.Bib]; ok && athlete.Bib != 0 {
+			return nil, fmt.Errorf("invalid athletes registry: bib %d is assigned to competitors %s and %s", athlete.Bib, owner, athlete.CompetitorID)
+		}
+		athletes[athlete.CompetitorID] = athlete
+		bibs[athlete.Bib] = athlete.CompetitorID
+	}
+
+	return athletes, nil
+}
+
+// ValidateAthletes проверяет, что каждый зарегистрированный (событие 1) участник
+// присутствует в реестре спортсменов. Пустой реестр считается отсутствующим и не проверяется.
+func ValidateAthletes(athletes map[string]*entities.Athlete, statistics storage.StatisticRepository) error {
+	if len(athletes) == 0 {
+		return nil
+	}
+
+	var unknown []string
+	for _, statistic := range statistics.ListStatistics() {
+		if athletes[statistic.CompetitorID] == nil {
+			unknown = append(unknown, statistic.CompetitorID)
+		}
+	}
+	if len(unknown) > 0 {
+		return fmt.Errorf("registered competitors are missing in athletes registry: %s", strings.Join(unknown, ", "))
+	}
+
+	return nil
+}
+
+// GetCompetitorName возвращает человекочитаемое представление участника:
+// идентификатор, стартовый номер, имя и страну, если спортсмен есть в реестре.
+func GetCompetitorName(competitorID string, athletes map[string]*entities.Athlete) string {
+	athlete := athletes[competitorID]
+	if athlete == nil {
+		return competitorID
+	}
+
+	name := fmt.Sprintf("%s #%d %s", competitorID, athlete.Bib, athlete.Name)
+	if athlete.Nation != "" {
+		name += fmt.Sprintf(" (%s)", athlete.Nation)
+	}
+
+	return name
+}
@@ -0,0 +1,110 @@
+package services_test
+
+import (
+	"os"
+	"path/filepath"
+	"system_prototype_for_biathlon_competitions/internal/entities"
+	"system_prototype_for_biathlon_competitions/internal/services"
+	"system_prototype_for_biathlon_competitions/internal/storage"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// openTempFile создаёт временный файл с указанным именем и содержимым и открывает его на чтение.
+func openTempFile(t *testing.T, name, content string) *os.File {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	t.Cleanup(func() { file.Close() })
+
+	return file
+}
+
+// TestParseAthletes тестирует метод ParseAthletes.
+func TestParseAthletes(t *testing.T) {
+	expected := &entities.Athlete{
+		CompetitorID: "1",
+		Bib:          101,
+		Name:         "Ivan Petrov",
+		Nation:       "RUS",
+		Gender:       "M",
+		Category:     "Senior",
+	}
+
+	t.Run("csv registry", func(t *testing.T) {
+		file := openTempFile(t, "athletes.csv", "competitorId,bib,name,nation,gender,category\n1,101,Ivan Petrov,RUS,M,Senior\n")
+		service := services.NewParseService(&entities.Files{AthletesFile: file})
+
+		athletes, err := service.ParseAthletes()
+		require.NoError(t, err)
+		require.Equal(t, map[string]*entities.Athlete{"1": expected}, athletes)
+	})
+
+	t.Run("json registry", func(t *testing.T) {
+		file := openTempFile(t, "athletes.json", `[{"competitorId": "1", "bib": 101, "name": "Ivan Petrov", "nation": "RUS", "gender": "M", "category": "Senior"}]`)
+		service := services.NewParseService(&entities.Files{AthletesFile: file})
+
+		athletes, err := service.ParseAthletes()
+		require.NoError(t, err)
+		require.Equal(t, map[string]*entities.Athlete{"1": expected}, athletes)
+	})
+
+	t.Run("no registry", func(t *testing.T) {
+		service := services.NewParseService(&entities.Files{})
+
+		athletes, err := service.ParseAthletes()
+		require.NoError(t, err)
+		require.Empty(t, athletes)
+	})
+
+	t.Run("duplicate bib", func(t *testing.T) {
+		file := openTempFile(t, "athletes.csv", "competitorId,bib,name,nation,gender,category\n1,101,Ivan Petrov,RUS,M,Senior\n2,101,Anna Sokolova,RUS,F,Senior\n")
+		service := services.NewParseService(&entities.Files{AthletesFile: file})
+
+		_, err := service.ParseAthletes()
+		require.Error(t, err)
+	})
+
+	t.Run("invalid header", func(t *testing.T) {
+		file := openTempFile(t, "athletes.csv", "id,bib,name,nation,gender,category\n1,101,Ivan Petrov,RUS,M,Senior\n")
+		service := services.NewParseService(&entities.Files{AthletesFile: file})
+
+		_, err := service.ParseAthletes()
+		require.Error(t, err)
+	})
+}
+
+// TestValidateAthletes тестирует функцию ValidateAthletes.
+func TestValidateAthletes(t *testing.T) {
+	athletes := map[string]*entities.Athlete{
+		"1": {CompetitorID: "1", Name: "Ivan Petrov"},
+	}
+
+	t.Run("all registered competitors are known", func(t *testing.T) {
+		statistics := storage.MemoryStatistics{"1": {CompetitorID: "1"}}
+		require.NoError(t, services.ValidateAthletes(athletes, statistics))
+	})
+
+	t.Run("unknown competitor", func(t *testing.T) {
+		statistics := storage.MemoryStatistics{"1": {CompetitorID: "1"}, "2": {CompetitorID: "2"}}
+		require.ErrorContains(t, services.ValidateAthletes(athletes, statistics), "2")
+	})
+
+	t.Run("empty registry", func(t *testing.T) {
+		statistics := storage.MemoryStatistics{"2": {CompetitorID: "2"}}
+		require.NoError(t, services.ValidateAthletes(nil, statistics))
+	})
+}
+
+// TestGetCompetitorName тестирует функцию GetCompetitorName.
+func TestGetCompetitorName(t *testing.T) {
+	athletes := map[string]*entities.Athlete{
+		"1": {CompetitorID: "1", Bib: 101, Name: "Ivan Petrov", Nation: "RUS"},
+	}
+
+	require.Equal(t, "1 #101 Ivan Petrov (RUS)", services.GetCompetitorName("1", athletes))
+	require.Equal(t, "2", services.GetCompetitorName("2", athletes))
+}
@@ -0,0 +1,224 @@
+package services
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"system_prototype_for_biathlon_competitions/internal/config"
+	"system_prototype_for_biathlon_competitions/internal/entities"
+	"system_prototype_for_biathlon_competitions/internal/messages"
+	"system_prototype_for_biathlon_competitions/internal/storage"
+	"time"
+)
+
+// Corrections file format (JSON):
+// [
+//   {"type": "time", "competitorId": "1", "value": "+00:00:10.000", "issuedBy": "Chief of competition", "reason": "Obstruction"},
+//   {"type": "dsq", "competitorId": "2", "rule": "IBU 7.4.c", "issuedBy": "Jury", "reason": "Shortened the course"},
+//   {"type": "reinstate", "competitorId": "3", "issuedBy": "Jury", "reason": "Start gate malfunction"},
+//   {"type": "void", "line": 12, "issuedBy": "Chief of timing", "reason": "Duplicate transponder read"},
+//   {"type": "replace", "line": 14, "event": "[10:00:01.744] 4 1", "issuedBy": "Chief of timing", "reason": "Mis-keyed time"},
+//   {"type": "void", "source": "finish.log", "line": 3, "issuedBy": "Chief of timing", "reason": "Double read"}
+// ]
+//
+// Without "source", line counts the merged stream of all event sources; with it, line counts the given source.
+//
+// Format of the audit section:
+// [index] type target details issued by <who>: <why>
+//
+// Example:
+// [1] time competitor(1) +00:00:10.000 issued by Chief of competition: Obstruction
+// [2] replace line(14) [10:00:01.744] 4 1 issued by Chief of timing: Mis-keyed time
+// [3] void line(finish.log:3) issued by Chief of timing: Double read
+
+// ParseCorrections считывает и парсит файл исправлений жюри в формате JSON.
+// Метод должен вызываться до ParseEvents, так как аннулирование и замена событий
+// применяются к строкам файла событий перед их обработкой.
+func (s *ParseService) ParseCorrections() ([]*entities.Correction, error) {
+	if s.files.CorrectionsFile == nil {
+		return nil, nil
+	}
+
+	var corrections []*entities.Correction
+	rd := bufio.NewReader(s.files.CorrectionsFile)
+	if err := json.NewDecoder(rd).Decode(&corrections); err != nil {
+		return nil, fmt.Errorf("failed to decode json: %w", err)
+	}
+	for i, correction := range corrections {
+		if err := validateCorrection(correction); err != nil {
+			return nil, fmt.Errorf("invalid correction %d: %w", i+1, err)
+		}
+	}
+	s.corrections = corrections
+
+	return corrections, nil
+}
+
+// validateCorrection проверяет наличие обязательных для вида исправления полей.
+func validateCorrection(correction *entities.Correction) error {
+	if correction.IssuedBy == "" || correction.Reason == "" {
+		return fmt.Errorf("issuedBy and reason are required")
+	}
+
+	switch correction.Type {
+	case entities.CorrectionTime:
+		if correction.CompetitorID == "" {
+			return fmt.Errorf("competitorId is required")
+		}
+		if _, err := parseSignedDuration(correction.Value); err != nil {
+			return err
+		}
+	case entities.CorrectionDSQ:
+		if correction.CompetitorID == "" || correction.Rule == "" {
+			return fmt.Errorf("competitorId and rule are required")
+		}
+	case entities.CorrectionReinstate:
+		if correction.CompetitorID == "" {
+			return fmt.Errorf("competitorId is required")
+		}
+	case entities.CorrectionVoid:
+		if correction.Line <= 0 {
+			return fmt.Errorf("positive line is required")
+		}
+	case entities.CorrectionReplace:
+		if correction.Line <= 0 || correction.Event == "" {
+			return fmt.Errorf("positive line and event are required")
+		}
+	default:
+		return fmt.Errorf("unknown correction type: %q", correction.Type)
+	}
+
+	return nil
+}
+
+// parseSignedDuration разбирает поправку времени вида +00:00:10.000 или -00:00:05.
+func parseSignedDuration(value string) (time.Duration, error) {
+	if len(value) < 2 || (value[0] != '+' && value[0] != '-') {
+		return 0, fmt.Errorf("time value must start with + or -: %q", value)
+	}
+
+	delta, err := config.ParseDuration(value[1:])
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse time value: %w", err)
+	}
+	if value[0] == '-' {
+		delta = -delta
+	}
+
+	return delta, nil
+}
+
+// applyEventCorrections аннулирует и заменяет строки событий по исправлениям жюри.
+// Номера строк ссылаются на строку источника correction.Source, найденную по ссылкам origins
+// объединённых событий, а без источника — на строку файла событий или объединённого потока.
+func applyEventCorrections(lines, origins []string, corrections []*entities.Correction) ([]eventLine, error) {
+	merged := make(map[string]int, len(origins))
+	for i, origin := range origins {
+		merged[origin] = i + 1
+	}
+
+	voided := make(map[int]bool)
+	replaced := make(map[int]string)
+	for _, correction := range corrections {
+		if correction.Type != entities.CorrectionVoid && correction.Type != entities.CorrectionReplace {
+			continue
+		}
+		line := correction.Line
+		if correction.Source != "" {
+			number, ok := merged[eventOrigin(correction.Source, correction.Line)]
+			if !ok {
+				return nil, fmt.Errorf("invalid correction: line %d of %s is not among merged events", correction.Line, correction.Source)
+			}
+			line = number
+		}
+		if line > len(lines) {
+			return nil, fmt.Errorf("invalid correction: line %d is out of events file", line)
+		}
+		if correction.Type == entities.CorrectionVoid {
+			voided[line] = true
+		} else {
+			replaced[line] = correction.Event
+		}
+	}
+
+	corrected := make([]eventLine, 0, len(lines))
+	for i, line := range lines {
+		lineNumber := i + 1
+		if voided[lineNumber] {
+			continue
+		}
+		if event, ok := replaced[lineNumber]; ok {
+			line = event
+		}
+		corrected = append(corrected, eventLine{number: lineNumber, text: line})
+	}
+
+	return corrected, nil
+}
+
+// ApplyCorrections применяет к статистике участников исправления жюри:
+// поправки времени, дисквалификации и восстановления.
+func ApplyCorrections(statistics storage.StatisticRepository, corrections []*entities.Correction) error {
+	for _, correction := range corrections {
+		switch correction.Type {
+		case entities.CorrectionTime, entities.CorrectionDSQ, entities.CorrectionReinstate:
+		default:
+			continue
+		}
+
+		statistic := statistics.GetStatistic(correction.CompetitorID)
+		if statistic == nil {
+			return fmt.Errorf("invalid correction: competitor %s is not registered", correction.CompetitorID)
+		}
+
+		switch correction.Type {
+		case entities.CorrectionTime:
+			delta, err := parseSignedDuration(correction.Value)
+			if err != nil {
+				return fmt.Errorf("invalid correction: %w", err)
+			}
+			statistic.TimeCorrection += delta
+		case entities.CorrectionDSQ:
+			statistic.DisqualificationRule = correction.Rule
+		case entities.CorrectionReinstate:
+			statistic.IsDisqualified = false
+			statistic.DisqualificationRule = ""
+		}
+	}
+
+	return nil
+}
+
+// FormatCorrection возвращает строковое представление исправления для раздела аудита отчёта
+// на языке каталога catalog.
+func FormatCorrection(index int, correction *entities.Correction, catalog *messages.Catalog) string {
+	var target, details string
+	switch correction.Type {
+	case entities.CorrectionTime:
+		target, details = catalog.Format(messages.AuditCompetitor, correction.CompetitorID), correction.Value
+	case entities.CorrectionDSQ:
+		target, details = catalog.Format(messages.AuditCompetitor, correction.CompetitorID), catalog.Format(messages.AuditRule, correction.Rule)
+	case entities.CorrectionReinstate:
+		target = catalog.Format(messages.AuditCompetitor, correction.CompetitorID)
+	case entities.CorrectionVoid:
+		target = formatCorrectionLine(correction, catalog)
+	case entities.CorrectionReplace:
+		target, details = formatCorrectionLine(correction, catalog), correction.Event
+	}
+
+	line := fmt.Sprintf("[%d] %s %s", index, correction.Type, target)
+	if details != "" {
+		line += " " + details
+	}
+
+	return catalog.Format(messages.AuditIssued, line, correction.IssuedBy, correction.Reason)
+}
+
+// formatCorrectionLine возвращает ссылку исправления на строку событий с источником, если он задан.
+func formatCorrectionLine(correction *entities.Correction, catalog *messages.Catalog) string {
+	if correction.Source != "" {
+		return catalog.Format(messages.AuditSourceLine, correction.Source, correction.Line)
+	}
+
+	return catalog.Format(messages.AuditLine, correction.Line)
+}
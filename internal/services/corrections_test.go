@@ -0,0 +1,104 @@
+package services_test
+
+import (
+	"bytes"
+	"system_prototype_for_biathlon_competitions/internal/config"
+	"system_prototype_for_biathlon_competitions/internal/entities"
+	"system_prototype_for_biathlon_competitions/internal/services"
+	"system_prototype_for_biathlon_competitions/internal/storage"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestParseCorrections тестирует метод ParseCorrections и применение исправлений к событиям.
+func TestParseCorrections(t *testing.T) {
+	t.Run("void and replace events", func(t *testing.T) {
+		corrections := `[
+			{"type": "void", "line": 2, "issuedBy": "Chief of timing", "reason": "Duplicate registration"},
+			{"type": "replace", "line": 3, "event": "[09:15:00.841] 2 1 09:30:00.000", "issuedBy": "Chief of timing", "reason": "Mis-keyed start time"}
+		]`
+		events := "[09:05:59.867] 1 1\n[09:05:59.867] 1 1\n[09:15:00.841] 2 1 09:31:00.000\n"
+		service := services.NewParseService(&entities.Files{
+			EventsFile:      openTempFile(t, "events", events),
+			CorrectionsFile: openTempFile(t, "corrections.json", corrections),
+		})
+
+		_, err := service.ParseCorrections()
+		require.NoError(t, err)
+		statistics, err := service.ParseEvents(&config.Config{Laps: 1, StartDelta: config.Duration(90 * time.Second)})
+		require.NoError(t, err)
+		require.Equal(t, time.Date(0, 1, 1, 9, 30, 0, 0, time.UTC), statistics.GetStatistic("1").RequiredStart)
+		require.Len(t, service.Events(), 3)
+	})
+
+	t.Run("missing reason", func(t *testing.T) {
+		service := services.NewParseService(&entities.Files{
+			CorrectionsFile: openTempFile(t, "corrections.json", `[{"type": "void", "line": 1, "issuedBy": "Jury"}]`),
+		})
+
+		_, err := service.ParseCorrections()
+		require.Error(t, err)
+	})
+
+	t.Run("unknown type", func(t *testing.T) {
+		service := services.NewParseService(&entities.Files{
+			CorrectionsFile: openTempFile(t, "corrections.json", `[{"type": "bonus", "issuedBy": "Jury", "reason": "Why not"}]`),
+		})
+
+		_, err := service.ParseCorrections()
+		require.Error(t, err)
+	})
+}
+
+// TestApplyCorrections тестирует функцию ApplyCorrections.
+func TestApplyCorrections(t *testing.T) {
+	statistics := storage.MemoryStatistics{
+		"1": {CompetitorID: "1"},
+		"2": {CompetitorID: "2"},
+		"3": {CompetitorID: "3", IsDisqualified: true},
+	}
+	corrections := []*entities.Correction{
+		{Type: entities.CorrectionTime, CompetitorID: "1", Value: "+00:00:10.000", IssuedBy: "Jury", Reason: "Obstruction"},
+		{Type: entities.CorrectionTime, CompetitorID: "1", Value: "-00:00:02", IssuedBy: "Jury", Reason: "Timing error"},
+		{Type: entities.CorrectionDSQ, CompetitorID: "2", Rule: "IBU 7.4.c", IssuedBy: "Jury", Reason: "Shortened the course"},
+		{Type: entities.CorrectionReinstate, CompetitorID: "3", IssuedBy: "Jury", Reason: "Start gate malfunction"},
+	}
+
+	require.NoError(t, services.ApplyCorrections(statistics, corrections))
+	require.Equal(t, 8*time.Second, statistics.GetStatistic("1").TimeCorrection)
+	require.Equal(t, "IBU 7.4.c", statistics.GetStatistic("2").DisqualificationRule)
+	require.Equal(t, "Disqualified(IBU 7.4.c)", services.GetTotalTime(statistics.GetStatistic("2"), nil))
+	require.False(t, statistics.GetStatistic("3").IsDisqualified)
+
+	t.Run("unknown competitor", func(t *testing.T) {
+		err := services.ApplyCorrections(statistics, []*entities.Correction{
+			{Type: entities.CorrectionReinstate, CompetitorID: "9", IssuedBy: "Jury", Reason: "Typo"},
+		})
+		require.Error(t, err)
+	})
+}
+
+// TestWriteResultingTableAudit тестирует раздел аудита в итоговой таблице.
+func TestWriteResultingTableAudit(t *testing.T) {
+	service := &services.ReportService{
+		Statistics: storage.MemoryStatistics{"1": {CompetitorID: "1"}},
+		Config:     &config.Config{Laps: 1},
+		Corrections: []*entities.Correction{
+			{Type: entities.CorrectionTime, CompetitorID: "1", Value: "+00:00:10.000", IssuedBy: "Chief of competition", Reason: "Obstruction"},
+			{Type: entities.CorrectionReplace, Line: 14, Event: "[10:00:01.744] 4 1", IssuedBy: "Chief of timing", Reason: "Mis-keyed time"},
+			{Type: entities.CorrectionVoid, Source: "finish.log", Line: 3, IssuedBy: "Chief of timing", Reason: "Double read"},
+		},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, service.WriteResultingTable(&buf))
+
+	expected := "[NotStarted] 1 [{,}] {,} 0/0\n" +
+		"\n== Audit ==\n" +
+		"[1] time competitor(1) +00:00:10.000 issued by Chief of competition: Obstruction\n" +
+		"[2] replace line(14) [10:00:01.744] 4 1 issued by Chief of timing: Mis-keyed time\n" +
+		"[3] void line(finish.log:3) issued by Chief of timing: Double read\n"
+	require.Equal(t, expected, buf.String())
+}
@@ -0,0 +1,158 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"system_prototype_for_biathlon_competitions/internal/clock"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Events file lines:
+// - fields are separated by any number of spaces or tabs; a trailing CR (CRLF line endings) is ignored
+// - blank lines and lines starting with # are skipped
+//
+// Format of a line error:
+// invalid incoming events: line N[ (source:line)][, column C]: expected <what>, found <what> in "<line>"
+//
+// Example:
+// invalid incoming events: line 3, column 20: expected start time HH:MM:SS.sss or YYYY-MM-DDTHH:MM:SS.sss,
+// found "10:00" in "[09:01:00.000] 2 1 10:00"
+
+// Описания ожидаемых элементов строки события.
+const (
+	expectedEventTime  = "event time [HH:MM:SS.sss]"
+	expectedTimestamp  = "time HH:MM:SS.sss or YYYY-MM-DDTHH:MM:SS.sss"
+	expectedEventID    = "event ID"
+	expectedCompetitor = "competitor ID"
+	expectedEndOfLine  = "end of line"
+)
+
+// eventCommentPrefix начинает строку-комментарий в файле событий.
+const eventCommentPrefix = "#"
+
+// LineError представляет собой ошибку в строке файла событий.
+type LineError struct {
+	Line     int    // Номер строки в файле событий (0, если неизвестен)
+	Origin   string // Источник и строка события при объединении нескольких источников
+	Column   int    // Позиция ошибочного элемента в строке (с 1; 0 — строка целиком)
+	Text     string // Исходная строка события
+	Expected string // Ожидаемый элемент
+	Found    string // Найденный элемент
+	Err      error  // Причина ошибки, если она не описывается ожидаемым и найденным элементами
+}
+
+// Error возвращает строковое представление ошибки с местом ошибки и исходной строкой.
+func (e *LineError) Error() string {
+	return "invalid incoming events: " + e.describe()
+}
+
+// describe возвращает место ошибки, её описание и исходную строку.
+func (e *LineError) describe() string {
+	var location []string
+	if e.Line > 0 {
+		location = append(location, fmt.Sprintf("line %d", e.Line))
+	}
+	if e.Origin != "" {
+		if len(location) > 0 {
+			location[0] += fmt.Sprintf(" (%s)", e.Origin)
+		} else {
+			location = append(location, e.Origin)
+		}
+	}
+	if e.Column > 0 {
+		location = append(location, fmt.Sprintf("column %d", e.Column))
+	}
+
+	message := fmt.Sprintf("expected %s, found %s", e.Expected, e.Found)
+	if e.Err != nil {
+		message = e.Err.Error()
+	}
+	if len(location) > 0 {
+		message = strings.Join(location, ", ") + ": " + message
+	}
+
+	return fmt.Sprintf("%s in %q", message, e.Text)
+}
+
+// Unwrap возвращает причину ошибки.
+func (e *LineError) Unwrap() error {
+	return e.Err
+}
+
+// eventToken представляет собой поле строки события с его позицией в строке.
+type eventToken struct {
+	text   string
+	column int
+}
+
+// tokenizeEventLine разбивает строку события на поля, разделённые пробелами и табуляциями.
+func tokenizeEventLine(line string) []eventToken {
+	var tokens []eventToken
+	start := -1
+	for i, r := range line + " " {
+		if !unicode.IsSpace(r) {
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+		if start >= 0 {
+			tokens = append(tokens, eventToken{text: line[start:i], column: utf8.RuneCountInString(line[:start]) + 1})
+			start = -1
+		}
+	}
+
+	return tokens
+}
+
+// isSkippedEventLine проверяет, что строка файла событий пуста или является комментарием.
+func isSkippedEventLine(line string) bool {
+	line = strings.TrimSpace(line)
+	return line == "" || strings.HasPrefix(line, eventCommentPrefix)
+}
+
+// ParseEventLine разбирает строку события. Время суток переносится на сутки опорного времени ref
+// с учётом перехода через полночь. Ошибки разбора возвращаются как *LineError.
+func ParseEventLine(line string, ref time.Time) (*Event, error) {
+	return parseEventLine(0, line, ref)
+}
+
+// parseEventLine разбирает строку события с номером number в файле событий.
+func parseEventLine(number int, line string, ref time.Time) (*Event, error) {
+	line = strings.TrimSuffix(line, "\r")
+	tokens := tokenizeEventLine(line)
+	event := &Event{line: number, text: line, tokens: tokens}
+	endOfLine := utf8.RuneCountInString(line) + 1
+	if len(tokens) == 0 {
+		return nil, event.lineError(endOfLine, expectedEventTime, expectedEndOfLine)
+	}
+
+	logTime := tokens[0]
+	if len(logTime.text) < 2 || !strings.HasPrefix(logTime.text, "[") || !strings.HasSuffix(logTime.text, "]") {
+		return nil, event.lineError(logTime.column, expectedEventTime, fmt.Sprintf("%q", logTime.text))
+	}
+	timestamp := logTime.text[1 : len(logTime.text)-1]
+	eventTime, err := clock.ParseTimestamp(timestamp, ref)
+	if err != nil {
+		return nil, event.lineError(logTime.column+1, expectedTimestamp, fmt.Sprintf("%q", timestamp))
+	}
+	if len(tokens) < 2 {
+		return nil, event.lineError(endOfLine, expectedEventID, expectedEndOfLine)
+	}
+	if len(tokens) < 3 {
+		return nil, event.lineError(endOfLine, expectedCompetitor, expectedEndOfLine)
+	}
+
+	event.Time = eventTime
+	event.LogTime = logTime.text
+	event.ID = tokens[1].text
+	event.CompetitorID = tokens[2].text
+	event.Params = make([]string, 0, len(tokens)-3)
+	for _, token := range tokens[3:] {
+		event.Params = append(event.Params, token.text)
+	}
+
+	return event, nil
+}
@@ -0,0 +1,138 @@
+package services_test
+
+import (
+	"system_prototype_for_biathlon_competitions/internal/config"
+	"system_prototype_for_biathlon_competitions/internal/entities"
+	"system_prototype_for_biathlon_competitions/internal/services"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestParseEventLine тестирует разбор строки события.
+func TestParseEventLine(t *testing.T) {
+	ref := time.Date(0, 1, 1, 9, 0, 0, 0, time.UTC)
+
+	event, err := services.ParseEventLine("[09:05:59.867]\t11  1 Lost in the  forest\r", ref)
+	require.NoError(t, err)
+	require.Equal(t, time.Date(0, 1, 1, 9, 5, 59, 867000000, time.UTC), event.Time)
+	require.Equal(t, "[09:05:59.867]", event.LogTime)
+	require.Equal(t, "11", event.ID)
+	require.Equal(t, "1", event.CompetitorID)
+	require.Equal(t, []string{"Lost", "in", "the", "forest"}, event.Params)
+
+	tests := []struct {
+		name string
+		line string
+		err  services.LineError
+	}{
+		{
+			name: "time without brackets",
+			line: "09:05:59.867 1 1",
+			err:  services.LineError{Column: 1, Expected: "event time [HH:MM:SS.sss]", Found: `"09:05:59.867"`},
+		},
+		{
+			name: "malformed time",
+			line: "  [9:05] 1 1",
+			err:  services.LineError{Column: 4, Expected: "time HH:MM:SS.sss or YYYY-MM-DDTHH:MM:SS.sss", Found: `"9:05"`},
+		},
+		{
+			name: "missing event ID",
+			line: "[09:05:59.867]",
+			err:  services.LineError{Column: 15, Expected: "event ID", Found: "end of line"},
+		},
+		{
+			name: "missing competitor ID",
+			line: "[09:05:59.867] 1",
+			err:  services.LineError{Column: 17, Expected: "competitor ID", Found: "end of line"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := services.ParseEventLine(tt.line, ref)
+			var lineErr *services.LineError
+			require.ErrorAs(t, err, &lineErr)
+			tt.err.Text = tt.line
+			require.Equal(t, &tt.err, lineErr)
+		})
+	}
+}
+
+// TestParseEventsLineErrors тестирует ошибки в строках файла событий в обычном и мягком режимах.
+func TestParseEventsLineErrors(t *testing.T) {
+	cfg := &config.Config{Laps: 1, StartDelta: config.Duration(90 * time.Second)}
+	events := "# start gate\r\n" +
+		"[09:00:00.000] 1 1\r\n" +
+		"\r\n" +
+		"[09:01:00.000]\t2  1 10:00\r\n" +
+		"[09:01:00.000] 2 2 10:00:00.000\r\n" +
+		"[09:02:00.000] 1 3\r\n" +
+		"[09:03:00.000] 5 3\r\n"
+
+	t.Run("stop at the first error", func(t *testing.T) {
+		service := services.NewParseService(&entities.Files{EventsFile: openTempFile(t, "events", events)})
+
+		_, err := service.ParseEvents(cfg)
+		require.EqualError(t, err, `invalid incoming events: line 4, column 21: expected start time HH:MM:SS.sss or YYYY-MM-DDTHH:MM:SS.sss, found "10:00" in "[09:01:00.000]\t2  1 10:00"`)
+	})
+
+	t.Run("collect all errors", func(t *testing.T) {
+		service := services.NewParseService(&entities.Files{EventsFile: openTempFile(t, "events", events)})
+		service.SetLenient(true)
+
+		statistics, err := service.ParseEvents(cfg)
+		require.NoError(t, err)
+		require.Len(t, statistics, 2)
+
+		var lineErrors []string
+		for _, lineErr := range service.LineErrors() {
+			lineErrors = append(lineErrors, lineErr.Error())
+		}
+		require.Equal(t, []string{
+			`invalid incoming events: line 4, column 21: expected start time HH:MM:SS.sss or YYYY-MM-DDTHH:MM:SS.sss, found "10:00" in "[09:01:00.000]\t2  1 10:00"`,
+			`invalid incoming events: line 5: competitor 2 is not registered in "[09:01:00.000] 2 2 10:00:00.000"`,
+			`invalid incoming events: line 7, column 19: expected firing range, found end of line in "[09:03:00.000] 5 3"`,
+		}, lineErrors)
+	})
+}
+
+// TestParseEventsIllegalEvents тестирует отклонение событий, которые отклоняет и проверка файлов:
+// попадания в несуществующую мишень и событий после финиша или схода участника.
+func TestParseEventsIllegalEvents(t *testing.T) {
+	cfg := &config.Config{Laps: 1, StartDelta: config.Duration(90 * time.Second)}
+	events := "[09:00:00.000] 1 1\n" +
+		"[09:00:00.000] 1 2\n" +
+		"[09:01:00.000] 2 1 09:30:00.000\n" +
+		"[09:01:00.000] 2 2 09:31:00.000\n" +
+		"[09:30:01.000] 4 1\n" +
+		"[09:31:01.000] 4 2\n" +
+		"[09:40:00.000] 5 1 1\n" +
+		"[09:40:10.000] 6 1 7\n" +
+		"[09:40:20.000] 7 1\n" +
+		"[09:45:00.000] 11 2 Lost in the forest\n" +
+		"[09:46:00.000] 10 2\n" +
+		"[09:50:00.000] 10 1\n" +
+		"[09:55:00.000] 10 1\n" +
+		"[10:00:00.000] 13 1 rule 1.2\n"
+	service := services.NewParseService(&entities.Files{EventsFile: openTempFile(t, "events", events)})
+	service.SetLenient(true)
+
+	statistics, err := service.ParseEvents(cfg)
+	require.NoError(t, err)
+	require.Zero(t, statistics.GetStatistic("1").NumberOfHits)
+	require.Equal(t, 1, statistics.GetStatistic("1").NumberOfEndedLaps)
+	require.Equal(t, "rule 1.2", statistics.GetStatistic("1").DisqualificationRule)
+	require.Zero(t, statistics.GetStatistic("2").NumberOfEndedLaps)
+
+	var lineErrors []string
+	for _, lineErr := range service.LineErrors() {
+		lineErrors = append(lineErrors, lineErr.Error())
+	}
+	require.Equal(t, []string{
+		`invalid incoming events: line 8, column 20: expected target 1-5, found "7" in "[09:40:10.000] 6 1 7"`,
+		`invalid incoming events: line 11: competitor 2 could not continue earlier in "[09:46:00.000] 10 2"`,
+		`invalid incoming events: line 13: competitor 1 has already finished in "[09:55:00.000] 10 1"`,
+	}, lineErrors)
+}
@@ -0,0 +1,169 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"system_prototype_for_biathlon_competitions/internal/clock"
+	"system_prototype_for_biathlon_competitions/internal/config"
+	"system_prototype_for_biathlon_competitions/internal/entities"
+	"system_prototype_for_biathlon_competitions/internal/messages"
+	"system_prototype_for_biathlon_competitions/internal/storage"
+	"time"
+	"unicode/utf8"
+)
+
+// Every incoming event is parsed into an Event and passed to the handler registered for its ID.
+// Events without a registered handler are ignored. Custom event types are added by registering
+// a handler (and, optionally, log messages with messages.Register):
+//
+//	handlers := services.DefaultEventHandlers()
+//	handlers.Register("20", func(ctx *services.EventContext, event *services.Event) error {
+//		ctx.Log(event, event.ID, event.Params[0])
+//		return nil
+//	})
+//	parseService.SetEventHandlers(handlers)
+
+// Event представляет собой разобранное входящее событие.
+type Event struct {
+	Time         time.Time // Время события
+	LogTime      string    // Метка времени в исходном виде, например [09:05:59.867]
+	ID           string    // Идентификатор события
+	CompetitorID string    // Идентификатор участника
+	Params       []string  // Дополнительные параметры события
+
+	line   int          // Номер строки в файле событий
+	text   string       // Исходная строка события
+	tokens []eventToken // Поля строки события с их позициями
+}
+
+// RequireParams проверяет, что у события есть дополнительные параметры с описаниями names
+// (например, "start time"), и для первого недостающего возвращает *LineError.
+func (e *Event) RequireParams(names ...string) error {
+	if len(e.Params) >= len(names) {
+		return nil
+	}
+
+	return e.lineError(utf8.RuneCountInString(e.text)+1, names[len(e.Params)], expectedEndOfLine)
+}
+
+// InvalidParam возвращает *LineError для дополнительного параметра события с индексом index,
+// не соответствующего описанию expected.
+func (e *Event) InvalidParam(index int, expected string) error {
+	token := e.tokens[3+index]
+	return e.lineError(token.column, expected, fmt.Sprintf("%q", token.text))
+}
+
+// lineError возвращает ошибку в строке события на позиции column.
+func (e *Event) lineError(column int, expected, found string) *LineError {
+	return &LineError{Line: e.line, Column: column, Text: e.text, Expected: expected, Found: found}
+}
+
+// EventHandler обрабатывает входящее событие, изменяя состояние гонки через ctx.
+type EventHandler func(ctx *EventContext, event *Event) error
+
+// EventHandlerRegistry представляет собой набор обработчиков входящих событий по их идентификаторам.
+type EventHandlerRegistry struct {
+	handlers map[string]EventHandler
+}
+
+// NewEventHandlerRegistry создаёт пустой набор обработчиков событий.
+func NewEventHandlerRegistry() *EventHandlerRegistry {
+	return &EventHandlerRegistry{handlers: make(map[string]EventHandler)}
+}
+
+// Register назначает обработчик handler событию eventID, заменяя ранее назначенный.
+func (r *EventHandlerRegistry) Register(eventID string, handler EventHandler) {
+	r.handlers[eventID] = handler
+}
+
+// Handler возвращает обработчик события eventID.
+func (r *EventHandlerRegistry) Handler(eventID string) (EventHandler, bool) {
+	handler, ok := r.handlers[eventID]
+	return handler, ok
+}
+
+// EventIDs возвращает идентификаторы событий, для которых назначены обработчики, в порядке номеров.
+func (r *EventHandlerRegistry) EventIDs() []string {
+	eventIDs := make([]string, 0, len(r.handlers))
+	for eventID := range r.handlers {
+		eventIDs = append(eventIDs, eventID)
+	}
+	slices.SortFunc(eventIDs, compareEventIDs)
+
+	return eventIDs
+}
+
+// compareEventIDs сравнивает идентификаторы событий сначала по длине, затем по алфавиту,
+// так что числовые идентификаторы упорядочиваются по возрастанию.
+func compareEventIDs(a, b string) int {
+	if len(a) != len(b) {
+		return len(a) - len(b)
+	}
+
+	return strings.Compare(a, b)
+}
+
+// EventContext предоставляет обработчикам событий доступ к состоянию гонки.
+type EventContext struct {
+	Config     *config.Config
+	Statistics storage.StatisticRepository
+	StartDelta time.Duration // Длительность стартового интервала
+
+	timers    *clock.Timers
+	catalog   *messages.Catalog
+	withdrawn map[string]bool // Участники, сошедшие с дистанции (событие 11)
+}
+
+// Competitor возвращает статистику зарегистрированного участника.
+func (c *EventContext) Competitor(competitorID string) (*entities.Statistic, error) {
+	statistic := c.Statistics.GetStatistic(competitorID)
+	if statistic == nil {
+		return nil, fmt.Errorf("competitor %s is not registered", competitorID)
+	}
+
+	return statistic, nil
+}
+
+// checkActive возвращает ошибку, если событие event недопустимо для зарегистрированного участника,
+// который уже финишировал или сошёл с дистанции.
+func (c *EventContext) checkActive(event *Event) error {
+	statistic := c.Statistics.GetStatistic(event.CompetitorID)
+	if statistic == nil || event.ID == "1" {
+		return nil
+	}
+	if key := closedCompetitorKey(event.ID, statistic.IsFinished, c.withdrawn[event.CompetitorID]); key != "" {
+		return errors.New(c.catalog.Format(key, event.CompetitorID))
+	}
+
+	return nil
+}
+
+// Schedule назначает действие action на момент deadline по часам гонки под ключом key.
+func (c *EventContext) Schedule(key string, deadline time.Time, action func(deadline time.Time)) {
+	c.timers.Schedule(key, deadline, action)
+}
+
+// Cancel отменяет действие, назначенное под ключом key.
+func (c *EventContext) Cancel(key string) {
+	c.timers.Cancel(key)
+}
+
+// Log выводит в журнал событий сообщение messageID (идентификатор события в каталоге сообщений)
+// с меткой времени и участником события event и параметрами params.
+func (c *EventContext) Log(event *Event, messageID string, params ...any) {
+	c.logAt(event.LogTime, messageID, event.CompetitorID, params...)
+}
+
+// logAt выводит в журнал событий сообщение messageID с меткой времени logTime для участника competitorID.
+func (c *EventContext) logAt(logTime, messageID, competitorID string, params ...any) {
+	message := c.catalog.Format(messages.EventKey(messageID), append([]any{competitorID}, params...)...)
+	fmt.Printf("%s %s\n", logTime, message)
+}
+
+// logAtDeadline выводит в журнал событий сообщение messageID для участника competitorID
+// с меткой времени срока deadline.
+func (c *EventContext) logAtDeadline(deadline time.Time, messageID, competitorID string, params ...any) {
+	c.logAt("["+deadline.Format("15:04:05.000")+"]", messageID, competitorID, params...)
+}
@@ -0,0 +1,58 @@
+package services_test
+
+import (
+	"system_prototype_for_biathlon_competitions/internal/config"
+	"system_prototype_for_biathlon_competitions/internal/entities"
+	"system_prototype_for_biathlon_competitions/internal/services"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestEventHandlerRegistry тестирует подключение пользовательских обработчиков событий.
+func TestEventHandlerRegistry(t *testing.T) {
+	var equipment []string
+	handlers := services.DefaultEventHandlers()
+	handlers.Register("20", func(ctx *services.EventContext, event *services.Event) error {
+		if err := event.RequireParams("equipment"); err != nil {
+			return err
+		}
+		if _, err := ctx.Competitor(event.CompetitorID); err != nil {
+			return err
+		}
+		equipment = append(equipment, event.CompetitorID+":"+event.Params[0])
+		return nil
+	})
+	require.Equal(t, []string{"1", "2", "3", "4", "5", "6", "7", "8", "9", "10", "11", "12", "13", "14", "20"}, handlers.EventIDs())
+
+	events := "[09:00:00.000] 1 1\n" +
+		"[09:30:00.000] 20 1 skis\n" +
+		"[09:31:00.000] 99 1\n"
+	service := services.NewParseService(&entities.Files{EventsFile: openTempFile(t, "events", events)})
+	service.SetEventHandlers(handlers)
+
+	_, err := service.ParseEvents(&config.Config{Laps: 1, StartDelta: config.Duration(90 * time.Second)})
+	require.NoError(t, err)
+	require.Equal(t, []string{"1:skis"}, equipment)
+
+	service = services.NewParseService(&entities.Files{EventsFile: openTempFile(t, "events", "[09:30:00.000] 20 2 skis\n")})
+	service.SetEventHandlers(handlers)
+	_, err = service.ParseEvents(&config.Config{Laps: 1, StartDelta: config.Duration(90 * time.Second)})
+	require.ErrorContains(t, err, "competitor 2 is not registered")
+
+	service = services.NewParseService(&entities.Files{
+		ConfigFile: openTempFile(t, "config", `{"laps": 1, "lapLen": 3000, "penaltyLen": 150, "firingLines": 1, "start": "09:30:00.000", "startDelta": "00:01:30"}`),
+		EventsFile: openTempFile(t, "events", events),
+	})
+	service.SetEventHandlers(handlers)
+	report, err := service.Validate()
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"== Syntax ==",
+		`warning: line 3, column 16: expected event ID 1-14, 20, found "99" in "[09:31:00.000] 99 1"`,
+		"== Competitors ==",
+		"warning: competitor 1 has no start time",
+		"0 errors, 2 warnings",
+	}, report.Lines())
+}
@@ -0,0 +1,80 @@
+package services
+
+import (
+	"fmt"
+	"system_prototype_for_biathlon_competitions/internal/config"
+	"time"
+)
+
+// Formatting policy of times and speeds in reports (config fields):
+// timePrecision - ms (default), hundredths, tenths or seconds
+// timeRounding  - truncate (default, FIS/IBU rules) or round
+// elideHours    - times under an hour are written without hours
+// speedUnit     - m/s (default) or km/h
+//
+// Example (tenths, truncate, elideHours, km/h):
+// [25:18.3] 2 [{12:38.2, 16.618}, {12:38.6, 16.610}] {01:40.0, 10.800} 8/10
+
+// timePrecisions задаёт единицу и количество знаков дробной части для каждой точности вывода времени.
+var timePrecisions = map[string]struct {
+	unit   time.Duration
+	digits int
+}{
+	"":                           {time.Millisecond, 3},
+	config.PrecisionMilliseconds: {time.Millisecond, 3},
+	config.PrecisionHundredths:   {10 * time.Millisecond, 2},
+	config.PrecisionTenths:       {100 * time.Millisecond, 1},
+	config.PrecisionSeconds:      {time.Second, 0},
+}
+
+// formatDuration возвращает строковое представление длительности d по правилам вывода из конфигурации.
+// Без конфигурации длительность выводится в формате 15:04:05.000, часы не ограничены сутками,
+// а отрицательная длительность выводится со знаком минус.
+func formatDuration(d time.Duration, cfg *config.Config) string {
+	precision := timePrecisions[""]
+	rounding, elideHours := "", false
+	if cfg != nil {
+		if p, found := timePrecisions[cfg.TimePrecision]; found {
+			precision = p
+		}
+		rounding, elideHours = cfg.TimeRounding, cfg.ElideHours
+	}
+
+	if rounding == config.RoundingRound {
+		d = d.Round(precision.unit)
+	} else {
+		d = d.Truncate(precision.unit)
+	}
+
+	return formatClock(d, precision.digits, elideHours)
+}
+
+// formatClock возвращает длительность d в виде [-]ЧЧ:ММ:СС с digits знаками дробной части секунд.
+// Количество часов не ограничено сутками, а при elideHours длительность меньше часа выводится без часов.
+func formatClock(d time.Duration, digits int, elideHours bool) string {
+	sign := ""
+	if d < 0 {
+		sign, d = "-", -d
+	}
+	hours, minutes, seconds := d/time.Hour, d/time.Minute%60, d/time.Second%60
+
+	clock := fmt.Sprintf("%s%02d:%02d:%02d", sign, hours, minutes, seconds)
+	if elideHours && hours == 0 {
+		clock = fmt.Sprintf("%s%02d:%02d", sign, minutes, seconds)
+	}
+	if digits > 0 {
+		fraction := fmt.Sprintf("%09d", d%time.Second)
+		clock += "." + fraction[:digits]
+	}
+
+	return clock
+}
+
+// formatSpeed возвращает строковое представление скорости metersPerSecond в единицах из конфигурации.
+func formatSpeed(metersPerSecond float64, cfg *config.Config) string {
+	if cfg != nil && cfg.SpeedUnit == config.SpeedUnitKMH {
+		return fmt.Sprintf("%.3f", metersPerSecond*3.6)
+	}
+
+	return fmt.Sprintf("%.3f", metersPerSecond)
+}
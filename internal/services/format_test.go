@@ -0,0 +1,87 @@
+package services_test
+
+import (
+	"system_prototype_for_biathlon_competitions/internal/config"
+	"system_prototype_for_biathlon_competitions/internal/entities"
+	"system_prototype_for_biathlon_competitions/internal/services"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestFormatPolicy тестирует вывод времени и скорости по правилам из конфигурации.
+func TestFormatPolicy(t *testing.T) {
+	start := time.Date(0, 1, 1, 10, 0, 0, 0, time.UTC)
+	statistic := &entities.Statistic{
+		IsFinished:                    true,
+		RequiredStart:                 start,
+		ActualStart:                   start,
+		ActualFinish:                  start.Add(25*time.Minute + 18*time.Second + 356*time.Millisecond),
+		TimeOfLapsCompletion:          []time.Time{start.Add(25*time.Minute + 18*time.Second + 356*time.Millisecond)},
+		TotalTimeOfPenaltyLaps:        50 * time.Second,
+		NumberOfCompletionPenaltyLaps: 2,
+	}
+
+	tests := []struct {
+		name      string
+		config    *config.Config
+		totalTime string
+		laps      string
+		penalty   string
+	}{
+		{
+			name:      "default",
+			config:    &config.Config{Laps: 1, LapLen: 3000, PenaltyLen: 150},
+			totalTime: "00:25:18.356",
+			laps:      "{00:25:18.356, 1.976}",
+			penalty:   "{00:00:50.000, 6.000}",
+		},
+		{
+			name:      "tenths truncated without hours in km/h",
+			config:    &config.Config{Laps: 1, LapLen: 3000, PenaltyLen: 150, TimePrecision: config.PrecisionTenths, ElideHours: true, SpeedUnit: config.SpeedUnitKMH},
+			totalTime: "25:18.3",
+			laps:      "{25:18.3, 7.113}",
+			penalty:   "{00:50.0, 21.600}",
+		},
+		{
+			name:      "hundredths rounded",
+			config:    &config.Config{Laps: 1, LapLen: 3000, PenaltyLen: 150, TimePrecision: config.PrecisionHundredths, TimeRounding: config.RoundingRound},
+			totalTime: "00:25:18.36",
+			laps:      "{00:25:18.36, 1.976}",
+			penalty:   "{00:00:50.00, 6.000}",
+		},
+		{
+			name:      "seconds",
+			config:    &config.Config{Laps: 1, LapLen: 3000, PenaltyLen: 150, TimePrecision: config.PrecisionSeconds},
+			totalTime: "00:25:18",
+			laps:      "{00:25:18, 1.976}",
+			penalty:   "{00:00:50, 6.000}",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.totalTime, services.GetTotalTime(statistic, tt.config))
+			require.Equal(t, tt.laps, services.GetTimeAndAvgSpeedForLaps(statistic, tt.config))
+			require.Equal(t, tt.penalty, services.GetTimeAndAvgSpeedForPenaltyLaps(statistic, tt.config))
+		})
+	}
+}
+
+// TestFormatDurationBeyondDay тестирует вывод длительностей от суток и отрицательных длительностей.
+func TestFormatDurationBeyondDay(t *testing.T) {
+	start := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	finished := func(elapsed time.Duration) *entities.Statistic {
+		return &entities.Statistic{IsFinished: true, RequiredStart: start, ActualStart: start, ActualFinish: start.Add(elapsed)}
+	}
+
+	require.Equal(t, "25:00:00.000", services.GetTotalTime(finished(25*time.Hour), nil))
+	require.Equal(t, "49:30:15.250", services.GetTotalTime(finished(49*time.Hour+30*time.Minute+15*time.Second+250*time.Millisecond), nil))
+	require.Equal(t, "-00:00:01.500", services.GetTotalTime(finished(-1500*time.Millisecond), nil))
+	require.Equal(t, "-01:02:03.000", services.GetTotalTime(finished(-(time.Hour+2*time.Minute+3*time.Second)), nil))
+
+	cfg := &config.Config{TimePrecision: config.PrecisionTenths, ElideHours: true}
+	require.Equal(t, "-00:01.5", services.GetTotalTime(finished(-1500*time.Millisecond), cfg))
+	require.Equal(t, "24:00:00.0", services.GetTotalTime(finished(24*time.Hour), cfg))
+}
@@ -0,0 +1,172 @@
+package services
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"system_prototype_for_biathlon_competitions/internal/entities"
+	"system_prototype_for_biathlon_competitions/internal/messages"
+)
+
+// Format:
+// The overall table is followed by a section per category and per gender.
+// Every group is ranked independently with the SortStatistics rules.
+// Competitors without a category (or gender) go to the last, Unassigned section; when no competitor
+// has one, the category (or gender) sections are omitted.
+//
+// Example:
+// == Overall ==
+// [00:25:18.356] 2 [{00:12:38.243, 4.616}, {00:12:38.610, 4.614}] {00:01:40.000, 3.000} 8/10
+// [00:25:26.047] 1 [{00:12:33.636, 4.644}, {00:12:50.667, 4.542}] {00:02:30.000, 3.000} 7/10
+//
+// == Category: Senior ==
+// ...
+//
+// == Gender: F ==
+// ...
+
+// UnassignedGroup обозначает группу участников, для которых категория или пол неизвестны.
+const UnassignedGroup = "Unassigned"
+
+// GetCategory возвращает категорию участника: из реестра спортсменов,
+// а при его отсутствии — указанную при регистрации.
+func GetCategory(statistic *entities.Statistic, athletes map[string]*entities.Athlete) string {
+	if athlete := athletes[statistic.CompetitorID]; athlete != nil && athlete.Category != "" {
+		return athlete.Category
+	}
+	if statistic.Category != "" {
+		return statistic.Category
+	}
+
+	return UnassignedGroup
+}
+
+// GetGender возвращает пол участника из реестра спортсменов.
+func GetGender(statistic *entities.Statistic, athletes map[string]*entities.Athlete) string {
+	if athlete := athletes[statistic.CompetitorID]; athlete != nil && athlete.Gender != "" {
+		return athlete.Gender
+	}
+
+	return UnassignedGroup
+}
+
+// GroupStatistics разбивает статистику участников на группы по ключу, возвращаемому key,
+// и упорядочивает каждую группу независимо по правилам SortStatistics.
+func (s *ReportService) GroupStatistics(key func(*entities.Statistic) string) map[string][]*entities.Statistic {
+	groups := make(map[string][]*entities.Statistic)
+	for _, statistic := range s.Statistics.ListStatistics() {
+		groupKey := key(statistic)
+		groups[groupKey] = append(groups[groupKey], statistic)
+	}
+
+	for groupKey, group := range groups {
+		groups[groupKey] = sortStatistics(group, s.Config)
+	}
+
+	return groups
+}
+
+// MakeGroupedTables создает таблицы результатов по категориям и полу и записывает их в файл 'report_groups'.
+func (s *ReportService) MakeGroupedTables() error {
+	reportFile, err := os.Create("report_groups")
+	if err != nil {
+		return fmt.Errorf("failed to create grouped report file: %w", err)
+	}
+	defer reportFile.Close()
+
+	return s.WriteGroupedTables(reportFile)
+}
+
+// WriteGroupedTables записывает в w общую таблицу результатов, а затем отдельные
+// таблицы для каждой категории и каждого пола. Разбиение пропускается, если категория
+// (или пол) не известны ни для одного участника. Если известен статус результатов,
+// отчёт предваряется заголовком со статусом и версией.
+func (s *ReportService) WriteGroupedTables(w io.Writer) error {
+	writer := bufio.NewWriter(w)
+	if err := s.writeStatusHeader(writer); err != nil {
+		return err
+	}
+	if err := writeSection(writer, s.Messages.Format(messages.ReportOverall), s.ResultingLines()); err != nil {
+		return err
+	}
+
+	err := s.writeGroups(writer, messages.ReportCategory, func(statistic *entities.Statistic) string {
+		return GetCategory(statistic, s.Athletes)
+	})
+	if err != nil {
+		return err
+	}
+	err = s.writeGroups(writer, messages.ReportGender, func(statistic *entities.Statistic) string {
+		return GetGender(statistic, s.Athletes)
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := writer.Flush(); err != nil {
+		return fmt.Errorf("failed to flush grouped report: %w", err)
+	}
+
+	return nil
+}
+
+// writeGroups записывает в writer таблицы групп по ключу key с заголовками по сообщению titleKey.
+// Если все участники попали в группу без назначения, таблицы не записываются.
+func (s *ReportService) writeGroups(writer *bufio.Writer, titleKey string, key func(*entities.Statistic) string) error {
+	groups := s.GroupStatistics(key)
+	if _, ok := groups[UnassignedGroup]; ok && len(groups) == 1 {
+		return nil
+	}
+	for _, group := range sortedGroupKeys(groups) {
+		if err := writeSection(writer, s.Messages.Format(titleKey, s.groupTitle(group)), s.formatLines(groups[group])); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// groupTitle возвращает название группы для заголовка раздела; группа без назначения
+// называется на языке каталога сообщений.
+func (s *ReportService) groupTitle(group string) string {
+	if group == UnassignedGroup {
+		return s.Messages.Format(messages.GroupUnassigned)
+	}
+
+	return group
+}
+
+// writeSection записывает в writer заголовок раздела и строки таблицы, отделяя разделы пустой строкой.
+func writeSection(writer *bufio.Writer, title string, lines []string) error {
+	if _, err := fmt.Fprintf(writer, "== %s ==\n", title); err != nil {
+		return fmt.Errorf("failed to write section header: %w", err)
+	}
+	for _, line := range lines {
+		if _, err := writer.WriteString(line + "\n"); err != nil {
+			return fmt.Errorf("failed to write line in grouped report: %w", err)
+		}
+	}
+	if _, err := writer.WriteString("\n"); err != nil {
+		return fmt.Errorf("failed to write section separator: %w", err)
+	}
+
+	return nil
+}
+
+// sortedGroupKeys возвращает ключи групп по алфавиту, помещая группу без назначения в конец.
+func sortedGroupKeys(groups map[string][]*entities.Statistic) []string {
+	keys := make([]string, 0, len(groups))
+	for key := range groups {
+		if key != UnassignedGroup {
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+	if _, ok := groups[UnassignedGroup]; ok {
+		keys = append(keys, UnassignedGroup)
+	}
+
+	return keys
+}
@@ -0,0 +1,127 @@
+package services_test
+
+import (
+	"bytes"
+	"system_prototype_for_biathlon_competitions/internal/config"
+	"system_prototype_for_biathlon_competitions/internal/entities"
+	"system_prototype_for_biathlon_competitions/internal/messages"
+	"system_prototype_for_biathlon_competitions/internal/services"
+	"system_prototype_for_biathlon_competitions/internal/storage"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestGetCategory тестирует функцию GetCategory.
+func TestGetCategory(t *testing.T) {
+	athletes := map[string]*entities.Athlete{
+		"1": {CompetitorID: "1", Category: "Junior"},
+	}
+
+	require.Equal(t, "Junior", services.GetCategory(&entities.Statistic{CompetitorID: "1", Category: "Senior"}, athletes))
+	require.Equal(t, "Youth", services.GetCategory(&entities.Statistic{CompetitorID: "2", Category: "Youth"}, athletes))
+	require.Equal(t, services.UnassignedGroup, services.GetCategory(&entities.Statistic{CompetitorID: "3"}, athletes))
+}
+
+// TestGroupStatistics тестирует метод GroupStatistics.
+func TestGroupStatistics(t *testing.T) {
+	start := time.Date(2023, 10, 1, 10, 0, 0, 0, time.UTC)
+	service := &services.ReportService{
+		Statistics: storage.MemoryStatistics{
+			"1": {CompetitorID: "1", Category: "Senior", IsFinished: true, RequiredStart: start, ActualFinish: start.Add(30 * time.Minute)},
+			"2": {CompetitorID: "2", Category: "Junior", IsFinished: true, RequiredStart: start, ActualFinish: start.Add(25 * time.Minute)},
+			"3": {CompetitorID: "3", Category: "Senior", IsFinished: true, RequiredStart: start, ActualFinish: start.Add(28 * time.Minute)},
+			"4": {CompetitorID: "4", Category: "Senior", IsDisqualified: true},
+		},
+	}
+
+	groups := service.GroupStatistics(func(statistic *entities.Statistic) string {
+		return services.GetCategory(statistic, service.Athletes)
+	})
+
+	require.Len(t, groups, 2)
+	require.Len(t, groups["Junior"], 1)
+	require.Len(t, groups["Senior"], 3)
+	require.Equal(t, "3", groups["Senior"][0].CompetitorID)
+	require.Equal(t, "1", groups["Senior"][1].CompetitorID)
+	require.Equal(t, "4", groups["Senior"][2].CompetitorID)
+}
+
+// TestWriteGroupedTables тестирует метод WriteGroupedTables.
+func TestWriteGroupedTables(t *testing.T) {
+	service := &services.ReportService{
+		Statistics: storage.MemoryStatistics{
+			"1": {CompetitorID: "1"},
+			"2": {CompetitorID: "2", Category: "Junior"},
+		},
+		Config: &config.Config{Laps: 1},
+		Athletes: map[string]*entities.Athlete{
+			"2": {CompetitorID: "2", Bib: 2, Name: "Anna Sokolova", Gender: "F"},
+		},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, service.WriteGroupedTables(&buf))
+
+	expected := "== Overall ==\n" +
+		"[NotStarted] 1 [{,}] {,} 0/0\n" +
+		"[NotStarted] 2 #2 Anna Sokolova [{,}] {,} 0/0\n" +
+		"\n" +
+		"== Category: Junior ==\n" +
+		"[NotStarted] 2 #2 Anna Sokolova [{,}] {,} 0/0\n" +
+		"\n" +
+		"== Category: Unassigned ==\n" +
+		"[NotStarted] 1 [{,}] {,} 0/0\n" +
+		"\n" +
+		"== Gender: F ==\n" +
+		"[NotStarted] 2 #2 Anna Sokolova [{,}] {,} 0/0\n" +
+		"\n" +
+		"== Gender: Unassigned ==\n" +
+		"[NotStarted] 1 [{,}] {,} 0/0\n" +
+		"\n"
+	require.Equal(t, expected, buf.String())
+}
+
+// TestWriteGroupedTablesUnassigned тестирует пропуск разбиения, если группа не известна ни одному участнику.
+func TestWriteGroupedTablesUnassigned(t *testing.T) {
+	service := &services.ReportService{
+		Statistics: storage.MemoryStatistics{
+			"1": {CompetitorID: "1"},
+			"2": {CompetitorID: "2"},
+		},
+		Config: &config.Config{Laps: 1},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, service.WriteGroupedTables(&buf))
+
+	expected := "== Overall ==\n" +
+		"[NotStarted] 1 [{,}] {,} 0/0\n" +
+		"[NotStarted] 2 [{,}] {,} 0/0\n" +
+		"\n"
+	require.Equal(t, expected, buf.String())
+}
+
+// TestWriteGroupedTablesRussian тестирует статус результатов и группу без назначения в отчёте на русском языке.
+func TestWriteGroupedTablesRussian(t *testing.T) {
+	catalog, err := messages.New(messages.LocaleRussian)
+	require.NoError(t, err)
+	service := &services.ReportService{
+		Statistics: storage.MemoryStatistics{
+			"1": {CompetitorID: "1"},
+			"2": {CompetitorID: "2", Category: "Junior"},
+		},
+		Config:   &config.Config{Laps: 1},
+		Status:   entities.ResultsOfficial,
+		Version:  3,
+		Messages: catalog,
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, service.WriteGroupedTables(&buf))
+
+	require.Contains(t, buf.String(), "== Результаты: Официальные, версия 3 ==\n")
+	require.Contains(t, buf.String(), "== Категория: Не указано ==\n")
+	require.NotContains(t, buf.String(), "Unassigned")
+}
@@ -0,0 +1,359 @@
+package services
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"system_prototype_for_biathlon_competitions/internal/clock"
+	"system_prototype_for_biathlon_competitions/internal/config"
+	"system_prototype_for_biathlon_competitions/internal/entities"
+	"system_prototype_for_biathlon_competitions/internal/messages"
+	"time"
+)
+
+// DefaultEventHandlers возвращает набор обработчиков встроенных входящих событий 1–14.
+func DefaultEventHandlers() *EventHandlerRegistry {
+	registry := NewEventHandlerRegistry()
+	registry.Register("1", handleRegistered)
+	registry.Register("2", handleStartTimeDrawn)
+	registry.Register("3", handleOnStartLine)
+	registry.Register("4", handleStarted)
+	registry.Register("5", handleOnFiringRange)
+	registry.Register("6", handleTargetHit)
+	registry.Register("7", handleLeftFiringRange)
+	registry.Register("8", handleEnteredPenaltyLaps)
+	registry.Register("9", handleLeftPenaltyLaps)
+	registry.Register("10", handleEndedLap)
+	registry.Register("11", handleCannotContinue)
+	registry.Register("12", handleLapped)
+	registry.Register("13", handleRuleDisqualification)
+	registry.Register("14", handleShotFired)
+
+	return registry
+}
+
+// protestWindowTimerKey — ключ таймера закрытия окна подачи протестов после финиша последнего участника.
+const protestWindowTimerKey = "protest"
+
+// startWindowTimerKey возвращает ключ таймера закрытия стартового интервала участника.
+func startWindowTimerKey(competitorID string) string {
+	return "start:" + competitorID
+}
+
+// cutoffTimerKey возвращает ключ таймера лимита времени участника на трассе.
+func cutoffTimerKey(competitorID string) string {
+	return "cutoff:" + competitorID
+}
+
+// closedCompetitorKey возвращает ключ сообщения о недопустимом событии eventID участника, который
+// уже финишировал (finished) или сошёл с дистанции (withdrawn), или пустую строку, если событие допустимо.
+// Дисквалификация за нарушение правил (событие 13) допустима и после финиша.
+func closedCompetitorKey(eventID string, finished, withdrawn bool) string {
+	switch {
+	case eventID == "13":
+		return ""
+	case finished:
+		return messages.ValidateFinished
+	case withdrawn:
+		return messages.ValidateWithdrawn
+	}
+
+	return ""
+}
+
+// parseFiringRange возвращает номер огневого рубежа из первого параметра события или *LineError,
+// если параметр не является номером рубежа (не больше числа огневых рубежей, если оно задано в cfg).
+func parseFiringRange(event *Event, cfg *config.Config) (int, error) {
+	firingRange, err := strconv.Atoi(event.Params[0])
+	if err != nil || firingRange < 1 || (cfg.FiringLines > 0 && firingRange > cfg.FiringLines) {
+		expected := "firing range number"
+		if cfg.FiringLines > 0 {
+			expected = fmt.Sprintf("firing range 1-%d", cfg.FiringLines)
+		}
+		return 0, event.InvalidParam(0, expected)
+	}
+
+	return firingRange, nil
+}
+
+// parseTarget возвращает номер мишени из первого параметра события или *LineError,
+// если параметр не является номером мишени огневого рубежа.
+func parseTarget(event *Event) (string, error) {
+	target := event.Params[0]
+	if number, err := strconv.Atoi(target); err != nil || number < 1 || number > targetsPerVisit {
+		return "", event.InvalidParam(0, fmt.Sprintf("target 1-%d", targetsPerVisit))
+	}
+
+	return target, nil
+}
+
+// handleRegistered регистрирует участника (событие 1) с необязательной категорией.
+func handleRegistered(ctx *EventContext, event *Event) error {
+	if ctx.Statistics.GetStatistic(event.CompetitorID) != nil {
+		return fmt.Errorf("competitor %s has already been registered", event.CompetitorID)
+	}
+	statistic := &entities.Statistic{CompetitorID: event.CompetitorID}
+	if len(event.Params) > 0 {
+		statistic.Category = event.Params[0]
+	}
+	ctx.Statistics.SaveStatistic(statistic)
+
+	ctx.Log(event, "1")
+	return nil
+}
+
+// handleStartTimeDrawn задаёт время старта участника по жеребьёвке (событие 2) и назначает
+// проверку старта на момент закрытия стартового интервала.
+func handleStartTimeDrawn(ctx *EventContext, event *Event) error {
+	if err := event.RequireParams("start time"); err != nil {
+		return err
+	}
+	statistic, err := ctx.Competitor(event.CompetitorID)
+	if err != nil {
+		return err
+	}
+	requiredStartStr := event.Params[0]
+	requiredStart, err := clock.ParseTimestamp(requiredStartStr, event.Time)
+	if err != nil {
+		return event.InvalidParam(0, "start "+expectedTimestamp)
+	}
+	statistic.RequiredStart = requiredStart
+
+	ctx.Schedule(startWindowTimerKey(event.CompetitorID), requiredStart.Add(ctx.StartDelta), func(deadline time.Time) {
+		timeSet := &TimeSet{
+			ActualTime: deadline.Add(time.Nanosecond),
+			StartDelta: ctx.StartDelta,
+		}
+		for _, statistic := range DisqualifiedCheck(ctx.Statistics, timeSet) {
+			ctx.logAtDeadline(statistic.DisqualifiedAt, "32", statistic.CompetitorID)
+		}
+	})
+
+	ctx.Log(event, "2", requiredStartStr)
+	return nil
+}
+
+// handleOnStartLine обрабатывает выход участника на стартовую линию (событие 3).
+func handleOnStartLine(ctx *EventContext, event *Event) error {
+	ctx.Log(event, "3")
+	return nil
+}
+
+// handleStarted фиксирует фактическое время старта участника (событие 4) и, если задан лимит времени
+// на трассе, назначает на момент его истечения исходящее событие 38.
+func handleStarted(ctx *EventContext, event *Event) error {
+	statistic, err := ctx.Competitor(event.CompetitorID)
+	if err != nil {
+		return err
+	}
+	statistic.ActualStart = event.Time
+	ctx.Cancel(startWindowTimerKey(event.CompetitorID))
+	if cutoff := time.Duration(ctx.Config.CutoffTime); cutoff > 0 {
+		ctx.Schedule(cutoffTimerKey(event.CompetitorID), event.Time.Add(cutoff), func(deadline time.Time) {
+			ctx.logAtDeadline(deadline, "38", event.CompetitorID)
+		})
+	}
+
+	ctx.Log(event, "4")
+	return nil
+}
+
+// handleOnFiringRange начинает посещение огневого рубежа (событие 5). Повторный выход на рубеж
+// до ухода с него не начинает нового посещения.
+func handleOnFiringRange(ctx *EventContext, event *Event) error {
+	if err := event.RequireParams("firing range"); err != nil {
+		return err
+	}
+	if _, err := parseFiringRange(event, ctx.Config); err != nil {
+		return err
+	}
+	statistic, err := ctx.Competitor(event.CompetitorID)
+	if err != nil {
+		return err
+	}
+	firingRange := event.Params[0]
+	if openPenaltyVisit(statistic) == nil {
+		statistic.NumberOfFiringRangeVisited++
+		statistic.NumberOfPenaltyLaps = targetsPerVisit
+		statistic.PenaltyVisits = append(statistic.PenaltyVisits, &entities.PenaltyVisit{FiringRange: firingRange, Arrived: event.Time})
+	}
+
+	ctx.Log(event, "5", firingRange)
+	return nil
+}
+
+// handleTargetHit засчитывает попадание в мишень (событие 6). Повторное попадание в ту же мишень
+// и попадание вне огневого рубежа не засчитываются.
+func handleTargetHit(ctx *EventContext, event *Event) error {
+	if err := event.RequireParams("target"); err != nil {
+		return err
+	}
+	target, err := parseTarget(event)
+	if err != nil {
+		return err
+	}
+	statistic, err := ctx.Competitor(event.CompetitorID)
+	if err != nil {
+		return err
+	}
+	if visit := openPenaltyVisit(statistic); visit != nil && recordHit(statistic, visit, target) {
+		recordShot(visit, event.Time)
+	}
+
+	ctx.Log(event, "6", target)
+	return nil
+}
+
+// handleShotFired фиксирует выстрел по мишени с его результатом (событие 14). Попадание засчитывается
+// так же, как событие 6, поэтому ленты, передающие оба события, не удваивают попадания.
+// Выстрел вне огневого рубежа не учитывается.
+func handleShotFired(ctx *EventContext, event *Event) error {
+	if err := event.RequireParams("target", "shot result"); err != nil {
+		return err
+	}
+	target, err := parseTarget(event)
+	if err != nil {
+		return err
+	}
+	result := event.Params[1]
+	hit, err := parseShotResult(result)
+	if err != nil {
+		return event.InvalidParam(1, "shot result "+ShotHit+" or "+ShotMiss)
+	}
+	statistic, err := ctx.Competitor(event.CompetitorID)
+	if err != nil {
+		return err
+	}
+	if visit := openPenaltyVisit(statistic); visit != nil {
+		visit.Shots = append(visit.Shots, entities.Shot{Time: event.Time, Target: target, Hit: hit})
+		recordShot(visit, event.Time)
+		if hit {
+			recordHit(statistic, visit, target)
+		}
+	}
+
+	ctx.Log(event, "14", target, result)
+	return nil
+}
+
+// handleLeftFiringRange завершает посещение огневого рубежа и фиксирует назначенные штрафные круги (событие 7).
+func handleLeftFiringRange(ctx *EventContext, event *Event) error {
+	statistic, err := ctx.Competitor(event.CompetitorID)
+	if err != nil {
+		return err
+	}
+	if visit := openPenaltyVisit(statistic); visit != nil {
+		visit.LeftRange = event.Time
+		visit.Owed = max(targetsPerVisit-len(visit.Hits), 0)
+	}
+
+	ctx.Log(event, "7")
+	return nil
+}
+
+// handleEnteredPenaltyLaps фиксирует вход на штрафные круги (событие 8).
+func handleEnteredPenaltyLaps(ctx *EventContext, event *Event) error {
+	if IsPenaltyTimeMode(ctx.Config) {
+		return fmt.Errorf("penalty laps are not allowed in penalty time mode")
+	}
+	statistic, err := ctx.Competitor(event.CompetitorID)
+	if err != nil {
+		return err
+	}
+	statistic.StartPenaltyLaps = event.Time
+	if visit := lastPenaltyVisit(statistic); visit != nil && visit.Entry.IsZero() {
+		visit.Entry = event.Time
+	}
+
+	ctx.Log(event, "8")
+	return nil
+}
+
+// handleLeftPenaltyLaps фиксирует выход со штрафных кругов (событие 9): назначенные после посещения
+// огневого рубежа круги считаются пройденными, а их время и количество добавляются к итогам участника.
+// Выход без входа на штрафные круги не засчитывается.
+func handleLeftPenaltyLaps(ctx *EventContext, event *Event) error {
+	if IsPenaltyTimeMode(ctx.Config) {
+		return fmt.Errorf("penalty laps are not allowed in penalty time mode")
+	}
+	statistic, err := ctx.Competitor(event.CompetitorID)
+	if err != nil {
+		return err
+	}
+	if visit := lastPenaltyVisit(statistic); visit != nil && !visit.Entry.IsZero() && visit.Exit.IsZero() {
+		visit.Exit = event.Time
+		completePenaltyVisit(statistic, visit, ctx.Config)
+	}
+
+	ctx.Log(event, "9")
+	return nil
+}
+
+// handleEndedLap засчитывает завершение основного круга (событие 10), а после последнего круга —
+// финиш участника (исходящее событие 33) и назначает закрытие окна подачи протестов (исходящее событие 37).
+func handleEndedLap(ctx *EventContext, event *Event) error {
+	statistic, err := ctx.Competitor(event.CompetitorID)
+	if err != nil {
+		return err
+	}
+	statistic.NumberOfEndedLaps++
+	statistic.TimeOfLapsCompletion = append(statistic.TimeOfLapsCompletion, event.Time)
+	if statistic.NumberOfEndedLaps != ctx.Config.Laps {
+		ctx.Log(event, "10")
+		return nil
+	}
+	statistic.IsFinished = true
+	statistic.ActualFinish = event.Time
+	ctx.Cancel(cutoffTimerKey(event.CompetitorID))
+	ctx.Schedule(protestWindowTimerKey, event.Time.Add(protestWindow(ctx.Config)), func(deadline time.Time) {
+		ctx.logAtDeadline(deadline, "37", event.CompetitorID)
+	})
+
+	ctx.Log(event, "33")
+	return nil
+}
+
+// handleCannotContinue обрабатывает сход участника с дистанции (событие 11) и выводит исходящее событие 34.
+func handleCannotContinue(ctx *EventContext, event *Event) error {
+	if _, err := ctx.Competitor(event.CompetitorID); err != nil {
+		return err
+	}
+	ctx.withdrawn[event.CompetitorID] = true
+	ctx.Cancel(cutoffTimerKey(event.CompetitorID))
+	ctx.Log(event, "11", strings.Join(event.Params, " "))
+	ctx.Log(event, "34")
+	return nil
+}
+
+// handleLapped отмечает участника, обойдённого на круг (событие 12), и выводит исходящее событие 35.
+func handleLapped(ctx *EventContext, event *Event) error {
+	statistic, err := ctx.Competitor(event.CompetitorID)
+	if err != nil {
+		return err
+	}
+	statistic.IsLapped = true
+	ctx.Cancel(cutoffTimerKey(event.CompetitorID))
+
+	ctx.Log(event, "12")
+	ctx.Log(event, "35")
+	return nil
+}
+
+// handleRuleDisqualification дисквалифицирует участника за нарушение правил (событие 13)
+// и выводит исходящее событие 36.
+func handleRuleDisqualification(ctx *EventContext, event *Event) error {
+	if err := event.RequireParams("rule"); err != nil {
+		return err
+	}
+	statistic, err := ctx.Competitor(event.CompetitorID)
+	if err != nil {
+		return err
+	}
+	rule := strings.Join(event.Params, " ")
+	statistic.DisqualificationRule = rule
+	ctx.Cancel(cutoffTimerKey(event.CompetitorID))
+
+	ctx.Log(event, "13", rule)
+	ctx.Log(event, "36", rule)
+	return nil
+}
@@ -0,0 +1,191 @@
+package services
+
+import (
+	"fmt"
+	"system_prototype_for_biathlon_competitions/internal/clock"
+	"system_prototype_for_biathlon_competitions/internal/config"
+	"system_prototype_for_biathlon_competitions/internal/entities"
+	"system_prototype_for_biathlon_competitions/internal/messages"
+	"system_prototype_for_biathlon_competitions/internal/storage"
+	"time"
+)
+
+// Results lifecycle:
+// Unofficial  - results were computed (every run of the race creates a new version)
+// Provisional - results were published, protests may be filed until the protest window closes
+// Official    - the protest window is closed and every protest has been decided
+//
+// The protest window is measured on the race clock from the finish of the last competitor.
+//
+// Format of the protests section:
+// [index] competitor(N) filed by <who> at <time>: <reason> - <decision> by <who>
+//
+// Example:
+// [1] competitor(2) filed by Team NOR at 10:40:00.000: Obstruction on lap 2 - rejected by Jury
+
+// ProtestDeadline возвращает время закрытия окна подачи протестов по часам гонки
+// и false, если ни один участник не финишировал и окно не открывалось.
+func ProtestDeadline(race *entities.Race) (time.Time, bool) {
+	if race.LastFinish.IsZero() {
+		return time.Time{}, false
+	}
+
+	return race.LastFinish.Add(protestWindow(race.Config)), true
+}
+
+// protestWindow возвращает окно подачи протестов из конфигурации cfg или окно по умолчанию.
+func protestWindow(cfg *config.Config) time.Duration {
+	if cfg != nil && cfg.ProtestWindow > 0 {
+		return time.Duration(cfg.ProtestWindow)
+	}
+
+	return config.DefaultProtestWindow
+}
+
+// ParseRaceTime разбирает время по часам гонки (например, время подачи протеста).
+// Время суток без даты переносится на сутки финиша последнего участника с учётом перехода через полночь.
+func ParseRaceTime(race *entities.Race, value string) (time.Time, error) {
+	ref := race.LastFinish
+	if ref.IsZero() {
+		ref = clock.Midnight
+		if race.Config != nil {
+			ref = race.Config.Date.Day()
+		}
+	}
+
+	return clock.ParseTimestamp(value, ref)
+}
+
+// GetLastFinish возвращает время финиша последнего финишировавшего участника.
+func GetLastFinish(statistics storage.StatisticRepository) time.Time {
+	var lastFinish time.Time
+	for _, statistic := range statistics.ListStatistics() {
+		if GetResultStatus(statistic) == entities.StatusFinished && (lastFinish.IsZero() || statistic.ActualFinish.After(lastFinish)) {
+			lastFinish = statistic.ActualFinish
+		}
+	}
+
+	return lastFinish
+}
+
+// PublishProvisional публикует неофициальные результаты гонки как предварительные,
+// открывая окно подачи протестов.
+func (s *RaceService) PublishProvisional(id string) (*entities.Race, error) {
+	race, err := s.repository.GetRace(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get race: %w", err)
+	}
+	if race.Status != entities.ResultsUnofficial {
+		return nil, fmt.Errorf("results with status %s cannot be published as provisional", race.Status)
+	}
+
+	race.Status = entities.ResultsProvisional
+	race.Version++
+
+	return race, s.updateRace(race)
+}
+
+// FileProtest прикрепляет протест к участнику гонки. Протесты принимаются только
+// для предварительных результатов и только до закрытия окна подачи протестов.
+func (s *RaceService) FileProtest(id string, protest *entities.Protest) (*entities.Race, error) {
+	race, err := s.repository.GetRace(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get race: %w", err)
+	}
+	if race.Status != entities.ResultsProvisional {
+		return nil, fmt.Errorf("protests are accepted only for provisional results, current status is %s", race.Status)
+	}
+	if race.Statistics[protest.CompetitorID] == nil {
+		return nil, fmt.Errorf("competitor %s is not registered", protest.CompetitorID)
+	}
+	if protest.FiledBy == "" || protest.Reason == "" {
+		return nil, fmt.Errorf("protest author and reason are required")
+	}
+
+	if deadline, ok := ProtestDeadline(race); ok && protest.SubmittedAt.After(deadline) {
+		return nil, fmt.Errorf("protest window closed at %s", deadline.Format("15:04:05.000"))
+	}
+
+	protest.Decision = entities.ProtestPending
+	race.Protests = append(race.Protests, protest)
+
+	return race, s.updateRace(race)
+}
+
+// DecideProtest фиксирует решение жюри по протесту с номером index (начиная с 1).
+func (s *RaceService) DecideProtest(id string, index int, decision, decidedBy string) (*entities.Race, error) {
+	race, err := s.repository.GetRace(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get race: %w", err)
+	}
+	if index < 1 || index > len(race.Protests) {
+		return nil, fmt.Errorf("protest %d not found", index)
+	}
+	if decision != entities.ProtestAccepted && decision != entities.ProtestRejected {
+		return nil, fmt.Errorf("unknown protest decision: %q", decision)
+	}
+	if decidedBy == "" {
+		return nil, fmt.Errorf("protest decision author is required")
+	}
+
+	protest := race.Protests[index-1]
+	if protest.Decision != entities.ProtestPending {
+		return nil, fmt.Errorf("protest %d has already been decided", index)
+	}
+	protest.Decision = decision
+	protest.DecidedBy = decidedBy
+	race.Version++
+
+	return race, s.updateRace(race)
+}
+
+// MakeOfficial утверждает предварительные результаты гонки после закрытия окна подачи
+// протестов, если все протесты рассмотрены. Результаты без финишировавших участников не утверждаются.
+func (s *RaceService) MakeOfficial(id string, at time.Time) (*entities.Race, error) {
+	race, err := s.repository.GetRace(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get race: %w", err)
+	}
+	if race.Status != entities.ResultsProvisional {
+		return nil, fmt.Errorf("only provisional results can become official, current status is %s", race.Status)
+	}
+
+	deadline, ok := ProtestDeadline(race)
+	if !ok {
+		return nil, fmt.Errorf("results without finishers cannot become official")
+	}
+	if at.Before(deadline) {
+		return nil, fmt.Errorf("protest window is open until %s", deadline.Format("15:04:05.000"))
+	}
+	for i, protest := range race.Protests {
+		if protest.Decision == entities.ProtestPending {
+			return nil, fmt.Errorf("protest %d is pending", i+1)
+		}
+	}
+
+	race.Status = entities.ResultsOfficial
+	race.Version++
+
+	return race, s.updateRace(race)
+}
+
+// updateRace сохраняет изменения статуса, версии и протестов гонки.
+func (s *RaceService) updateRace(race *entities.Race) error {
+	if err := s.repository.SaveRace(race); err != nil {
+		return fmt.Errorf("failed to save race: %w", err)
+	}
+
+	return nil
+}
+
+// FormatProtest возвращает строковое представление протеста для раздела протестов отчёта
+// на языке каталога catalog.
+func FormatProtest(index int, protest *entities.Protest, catalog *messages.Catalog) string {
+	submittedAtStr := protest.SubmittedAt.Format("15:04:05.000")
+	line := catalog.Format(messages.ProtestFiled, index, protest.CompetitorID, protest.FiledBy, submittedAtStr, protest.Reason)
+	if protest.Decision == entities.ProtestPending {
+		return catalog.Format(messages.ProtestPending, line)
+	}
+
+	return catalog.Format(messages.ProtestDecided, line, protest.Decision, protest.DecidedBy)
+}
@@ -0,0 +1,160 @@
+package services_test
+
+import (
+	"bytes"
+	"strings"
+	"system_prototype_for_biathlon_competitions/internal/config"
+	"system_prototype_for_biathlon_competitions/internal/entities"
+	"system_prototype_for_biathlon_competitions/internal/services"
+	"system_prototype_for_biathlon_competitions/internal/storage"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// newTestRace создаёт гонку с одним финишировавшим участником.
+func newTestRace(id string) *entities.Race {
+	return &entities.Race{
+		ID:     id,
+		Config: &config.Config{Laps: 1, ProtestWindow: config.Duration(15 * time.Minute)},
+		Statistics: map[string]*entities.Statistic{
+			"1": {
+				CompetitorID:  "1",
+				IsFinished:    true,
+				RequiredStart: time.Date(0, 1, 1, 10, 0, 0, 0, time.UTC),
+				ActualFinish:  time.Date(0, 1, 1, 10, 30, 0, 0, time.UTC),
+			},
+		},
+	}
+}
+
+// saveTestRace сохраняет гонку со статистикой участников из race.Statistics.
+func saveTestRace(raceService *services.RaceService, race *entities.Race) error {
+	return raceService.SaveRace(race, storage.MemoryStatistics(race.Statistics))
+}
+
+// TestResultsLifecycle тестирует переходы статусов результатов гонки и подачу протестов.
+func TestResultsLifecycle(t *testing.T) {
+	fileStorage, err := storage.NewFileStorage(t.TempDir())
+	require.NoError(t, err)
+	raceService := services.NewRaceService(fileStorage)
+
+	race := newTestRace("sprint")
+	require.NoError(t, saveTestRace(raceService, race))
+	require.Equal(t, entities.ResultsUnofficial, race.Status)
+	require.Equal(t, 1, race.Version)
+
+	_, err = raceService.FileProtest("sprint", &entities.Protest{CompetitorID: "1", FiledBy: "Team", Reason: "Obstruction"})
+	require.Error(t, err, "protests are not accepted for unofficial results")
+
+	race, err = raceService.PublishProvisional("sprint")
+	require.NoError(t, err)
+	require.Equal(t, entities.ResultsProvisional, race.Status)
+	require.Equal(t, 2, race.Version)
+
+	_, err = raceService.FileProtest("sprint", &entities.Protest{
+		CompetitorID: "1",
+		SubmittedAt:  time.Date(0, 1, 1, 10, 50, 0, 0, time.UTC),
+		FiledBy:      "Team",
+		Reason:       "Obstruction",
+	})
+	require.Error(t, err, "protest window is closed")
+
+	_, err = raceService.FileProtest("sprint", &entities.Protest{
+		CompetitorID: "1",
+		SubmittedAt:  time.Date(0, 1, 1, 10, 40, 0, 0, time.UTC),
+		FiledBy:      "Team",
+		Reason:       "Obstruction",
+	})
+	require.NoError(t, err)
+
+	_, err = raceService.MakeOfficial("sprint", time.Date(0, 1, 1, 10, 40, 0, 0, time.UTC))
+	require.Error(t, err, "protest window is still open")
+	_, err = raceService.MakeOfficial("sprint", time.Date(0, 1, 1, 10, 50, 0, 0, time.UTC))
+	require.Error(t, err, "protest is pending")
+
+	race, err = raceService.DecideProtest("sprint", 1, entities.ProtestRejected, "Jury")
+	require.NoError(t, err)
+	require.Equal(t, 3, race.Version)
+
+	race, err = raceService.MakeOfficial("sprint", time.Date(0, 1, 1, 10, 50, 0, 0, time.UTC))
+	require.NoError(t, err)
+	require.Equal(t, entities.ResultsOfficial, race.Status)
+	require.Equal(t, 4, race.Version)
+
+	require.Error(t, saveTestRace(raceService, newTestRace("sprint")), "official results cannot be overwritten")
+
+	var buf bytes.Buffer
+	require.NoError(t, raceService.ExportRace("sprint", &buf, nil))
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Equal(t, "== Results: Official, version 4 ==", lines[0])
+	require.Equal(t, "[1] competitor(1) filed by Team at 10:40:00.000: Obstruction - rejected by Jury", lines[len(lines)-1])
+}
+
+// TestResultsLifecycleWithoutFinishers тестирует, что результаты без финишировавших участников не утверждаются.
+func TestResultsLifecycleWithoutFinishers(t *testing.T) {
+	fileStorage, err := storage.NewFileStorage(t.TempDir())
+	require.NoError(t, err)
+	raceService := services.NewRaceService(fileStorage)
+
+	race := newTestRace("sprint")
+	race.Statistics["1"] = &entities.Statistic{CompetitorID: "1", ActualStart: time.Date(0, 1, 1, 10, 0, 0, 0, time.UTC)}
+	require.NoError(t, saveTestRace(raceService, race))
+	require.True(t, race.LastFinish.IsZero())
+
+	race, err = raceService.PublishProvisional("sprint")
+	require.NoError(t, err)
+	_, ok := services.ProtestDeadline(race)
+	require.False(t, ok)
+
+	_, err = raceService.FileProtest("sprint", &entities.Protest{
+		CompetitorID: "1",
+		SubmittedAt:  time.Date(0, 1, 1, 11, 0, 0, 0, time.UTC),
+		FiledBy:      "Team",
+		Reason:       "Course marking",
+	})
+	require.NoError(t, err)
+	_, err = raceService.DecideProtest("sprint", 1, entities.ProtestRejected, "Jury")
+	require.NoError(t, err)
+
+	_, err = raceService.MakeOfficial("sprint", time.Date(0, 1, 1, 12, 0, 0, 0, time.UTC))
+	require.ErrorContains(t, err, "results without finishers cannot become official")
+}
+
+// TestSaveRaceVersions тестирует нумерацию версий при повторном сохранении гонки.
+func TestSaveRaceVersions(t *testing.T) {
+	fileStorage, err := storage.NewFileStorage(t.TempDir())
+	require.NoError(t, err)
+	raceService := services.NewRaceService(fileStorage)
+
+	require.NoError(t, saveTestRace(raceService, newTestRace("sprint")))
+	_, err = raceService.PublishProvisional("sprint")
+	require.NoError(t, err)
+
+	race := newTestRace("sprint")
+	require.NoError(t, saveTestRace(raceService, race))
+	require.Equal(t, entities.ResultsUnofficial, race.Status)
+	require.Equal(t, 3, race.Version)
+	require.Equal(t, time.Date(0, 1, 1, 10, 30, 0, 0, time.UTC), race.LastFinish)
+}
+
+// TestParseRaceTime тестирует разбор времени по часам гонки относительно финиша последнего участника.
+func TestParseRaceTime(t *testing.T) {
+	race := newTestRace("night")
+	race.Config.Date = config.Date(time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC))
+	race.LastFinish = time.Date(2024, 1, 15, 23, 55, 0, 0, time.UTC)
+
+	submittedAt, err := services.ParseRaceTime(race, "00:05:00.000")
+	require.NoError(t, err)
+	require.Equal(t, time.Date(2024, 1, 16, 0, 5, 0, 0, time.UTC), submittedAt)
+
+	submittedAt, err = services.ParseRaceTime(race, "2024-01-16T09:00:00.000")
+	require.NoError(t, err)
+	require.Equal(t, time.Date(2024, 1, 16, 9, 0, 0, 0, time.UTC), submittedAt)
+
+	race.LastFinish = time.Time{}
+	submittedAt, err = services.ParseRaceTime(race, "10:00:00.000")
+	require.NoError(t, err)
+	require.Equal(t, time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC), submittedAt)
+}
@@ -0,0 +1,156 @@
+package services
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"system_prototype_for_biathlon_competitions/internal/config"
+	"system_prototype_for_biathlon_competitions/internal/entities"
+	"system_prototype_for_biathlon_competitions/internal/messages"
+	"time"
+)
+
+// Several events files (e.g. start gate, firing range, finish) are merged into one stream:
+// - events are taken in time order; the order of events within one source is kept
+// - events with equal time are taken in the order the sources are given
+// - an event already taken from another source (same time, event, competitor and parameters, whatever the
+//   spacing or line ending) is a duplicate and is dropped
+// Every merged event keeps its origin <source>:<line> in the race record.
+//
+// Format of the duplicates summary:
+// <origin>: [event] duplicates <origin>
+//
+// Example:
+// finish:12: [10:24:49.905] 10 1 duplicates range:31
+
+// DuplicateEvent представляет собой строку события, повторяющую событие из другого источника.
+type DuplicateEvent struct {
+	Origin   string // Источник и строка отброшенного события
+	Event    string // Строка события
+	Original string // Источник и строка принятого события
+}
+
+// sourceEvent представляет собой строку события источника с разобранным временем.
+type sourceEvent struct {
+	source int
+	origin string
+	text   string
+	time   time.Time
+	key    string // Время, идентификатор, участник и параметры разобранного события для поиска повторов
+}
+
+// readEventLines считывает строки событий из r. Последняя строка может не заканчиваться переводом строки.
+func readEventLines(r io.Reader) ([]string, error) {
+	var lines []string
+	reader := bufio.NewReader(r)
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return nil, fmt.Errorf("failed to read event: %w", err)
+		}
+		line = strings.TrimSuffix(line, "\n")
+		if line != "" || err == nil {
+			lines = append(lines, line)
+		}
+		if err == io.EOF {
+			return lines, nil
+		}
+	}
+}
+
+// eventOrigin возвращает ссылку на строку line источника событий name.
+func eventOrigin(name string, line int) string {
+	return fmt.Sprintf("%s:%d", name, line)
+}
+
+// readEventSource считывает события источника с номером index и разбирает их время
+// с учётом перехода через полночь. Пустые строки и комментарии пропускаются, ошибки в строках
+// передаются в report, и строка пропускается, если report вернул nil.
+func readEventSource(index int, source *entities.EventSource, raceDay time.Time, report func(error) error) ([]*sourceEvent, error) {
+	lines, err := readEventLines(source.File)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", source.Name, err)
+	}
+
+	events := make([]*sourceEvent, 0, len(lines))
+	last := raceDay
+	for i, line := range lines {
+		if isSkippedEventLine(line) {
+			continue
+		}
+		origin := eventOrigin(source.Name, i+1)
+		event, err := parseEventLine(0, line, last)
+		if err != nil {
+			var lineErr *LineError
+			if errors.As(err, &lineErr) {
+				lineErr.Origin = origin
+			}
+			if err := report(err); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if event.Time.After(last) {
+			last = event.Time
+		}
+		key := fmt.Sprintf("%s %s %s %q", event.Time, event.ID, event.CompetitorID, event.Params)
+		events = append(events, &sourceEvent{source: index, origin: origin, text: line, time: event.Time, key: key})
+	}
+
+	return events, nil
+}
+
+// mergeEventSources объединяет события нескольких источников в один упорядоченный по времени поток
+// и возвращает его вместе с отброшенными повторами. Ошибки в строках передаются в report.
+func mergeEventSources(sources []*entities.EventSource, cfg *config.Config, report func(error) error) ([]*sourceEvent, []*DuplicateEvent, error) {
+	queues := make([][]*sourceEvent, len(sources))
+	for i, source := range sources {
+		queue, err := readEventSource(i, source, cfg.Date.Day(), report)
+		if err != nil {
+			return nil, nil, err
+		}
+		queues[i] = queue
+	}
+
+	var merged []*sourceEvent
+	var duplicates []*DuplicateEvent
+	taken := make(map[string]*sourceEvent)
+	for {
+		next := -1
+		for i, queue := range queues {
+			if len(queue) > 0 && (next < 0 || queue[0].time.Before(queues[next][0].time)) {
+				next = i
+			}
+		}
+		if next < 0 {
+			return merged, duplicates, nil
+		}
+
+		event := queues[next][0]
+		queues[next] = queues[next][1:]
+		if original := taken[event.key]; original != nil && original.source != event.source {
+			duplicates = append(duplicates, &DuplicateEvent{Origin: event.origin, Event: event.text, Original: original.origin})
+			continue
+		}
+		taken[event.key] = event
+		merged = append(merged, event)
+	}
+}
+
+// EventOrigins возвращает источник и строку каждого события, прочитанного методом ParseEvents
+// из нескольких источников, в том же порядке, что и Events.
+func (s *ParseService) EventOrigins() []string {
+	return s.origins
+}
+
+// DuplicateEvents возвращает события, отброшенные при объединении источников как повторы.
+func (s *ParseService) DuplicateEvents() []*DuplicateEvent {
+	return s.duplicates
+}
+
+// FormatDuplicateEvent возвращает строковое представление отброшенного повтора события на языке каталога catalog.
+func FormatDuplicateEvent(duplicate *DuplicateEvent, catalog *messages.Catalog) string {
+	return catalog.Format(messages.DiagnosticDuplicate, duplicate.Origin, duplicate.Event, duplicate.Original)
+}
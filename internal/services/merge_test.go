@@ -0,0 +1,112 @@
+package services_test
+
+import (
+	"system_prototype_for_biathlon_competitions/internal/config"
+	"system_prototype_for_biathlon_competitions/internal/entities"
+	"system_prototype_for_biathlon_competitions/internal/services"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestParseEventsMergeSources тестирует объединение событий нескольких устройств хронометража.
+func TestParseEventsMergeSources(t *testing.T) {
+	gate := "[09:00:00.000] 1 1\n" +
+		"[09:01:00.000] 2 1 10:00:00.000\n" +
+		"[10:00:01.000] 4 1\n"
+	ranges := "[10:00:01.000] 3 1\n" +
+		"[10:05:00.000] 5 1 1\n" +
+		"[10:05:10.000] 7 1\n"
+	finish := "[10:05:00.000] 5 1 1\n" +
+		"[10:10:00.000] 10 1\n"
+	files := &entities.Files{EventSources: []*entities.EventSource{
+		{Name: "gate", File: openTempFile(t, "gate", gate)},
+		{Name: "range", File: openTempFile(t, "range", ranges)},
+		{Name: "finish", File: openTempFile(t, "finish", finish)},
+	}}
+	service := services.NewParseService(files)
+
+	statistics, err := service.ParseEvents(&config.Config{Laps: 1, StartDelta: config.Duration(90 * time.Second)})
+	require.NoError(t, err)
+	require.True(t, statistics.GetStatistic("1").IsFinished)
+	require.Equal(t, 1, statistics.GetStatistic("1").NumberOfFiringRangeVisited)
+
+	require.Equal(t, []string{
+		"[09:00:00.000] 1 1",
+		"[09:01:00.000] 2 1 10:00:00.000",
+		"[10:00:01.000] 4 1",
+		"[10:00:01.000] 3 1",
+		"[10:05:00.000] 5 1 1",
+		"[10:05:10.000] 7 1",
+		"[10:10:00.000] 10 1",
+	}, service.Events())
+	require.Equal(t, []string{"gate:1", "gate:2", "gate:3", "range:1", "range:2", "range:3", "finish:2"}, service.EventOrigins())
+
+	require.Len(t, service.DuplicateEvents(), 1)
+	require.Equal(t, "finish:1: [10:05:00.000] 5 1 1 duplicates range:2", services.FormatDuplicateEvent(service.DuplicateEvents()[0], nil))
+}
+
+// TestParseEventsMergeSourcesDuplicateSpacing тестирует отбрасывание повторов, отличающихся пробелами и концом строки.
+func TestParseEventsMergeSourcesDuplicateSpacing(t *testing.T) {
+	files := &entities.Files{EventSources: []*entities.EventSource{
+		{Name: "gate", File: openTempFile(t, "gate", "[09:00:00.000] 1 1\n[09:01:00.000] 2 1 09:30:00.000\n[09:30:01.000] 4 1\n")},
+		{Name: "finish", File: openTempFile(t, "finish", "[09:00:00.000] 1 1\r\n[09:30:01.000]  4\t1\n")},
+	}}
+	service := services.NewParseService(files)
+
+	_, err := service.ParseEvents(&config.Config{Laps: 1, StartDelta: config.Duration(90 * time.Second)})
+	require.NoError(t, err)
+	require.Equal(t, []string{"gate:1", "gate:2", "gate:3"}, service.EventOrigins())
+	require.Len(t, service.DuplicateEvents(), 2)
+}
+
+// TestParseEventsMergeSourcesOrder тестирует ссылку на источник в диагностике нарушения порядка событий.
+func TestParseEventsMergeSourcesOrder(t *testing.T) {
+	files := &entities.Files{EventSources: []*entities.EventSource{
+		{Name: "gate", File: openTempFile(t, "gate", "[09:00:00.000] 1 1\n[09:00:02.000] 1 2\n[09:00:01.000] 1 3\n")},
+		{Name: "finish", File: openTempFile(t, "finish", "[09:00:03.000] 1 4\n")},
+	}}
+	service := services.NewParseService(files)
+
+	_, err := service.ParseEvents(&config.Config{Laps: 1, StartDelta: config.Duration(90 * time.Second), EventOrder: config.EventOrderWarn})
+	require.NoError(t, err)
+	require.Len(t, service.OrderDiagnostics(), 1)
+	require.Equal(t, "line 3 (gate:3): [09:00:01.000] 1 3 is 00:00:01.000 earlier than previous event, accepted",
+		services.FormatOrderDiagnostic(service.OrderDiagnostics()[0], nil))
+}
+
+// TestParseEventsMergeSourcesCorrections тестирует исправления, ссылающиеся на строку источника событий.
+func TestParseEventsMergeSourcesCorrections(t *testing.T) {
+	newService := func(corrections string) *services.ParseService {
+		return services.NewParseService(&entities.Files{
+			EventSources: []*entities.EventSource{
+				{Name: "gate", File: openTempFile(t, "gate", "[09:00:00.000] 1 1\n[09:00:02.000] 1 2\n")},
+				{Name: "finish", File: openTempFile(t, "finish", "[09:00:01.000] 1 3\n[09:00:03.000] 1 4\n")},
+			},
+			CorrectionsFile: openTempFile(t, "corrections.json", corrections),
+		})
+	}
+
+	t.Run("source line", func(t *testing.T) {
+		service := newService(`[
+			{"type": "void", "source": "finish", "line": 1, "issuedBy": "Chief of timing", "reason": "Double read"},
+			{"type": "replace", "line": 4, "event": "[09:00:03.000] 1 5", "issuedBy": "Chief of timing", "reason": "Mis-keyed competitor"}
+		]`)
+		_, err := service.ParseCorrections()
+		require.NoError(t, err)
+		statistics, err := service.ParseEvents(&config.Config{Laps: 1, StartDelta: config.Duration(90 * time.Second)})
+		require.NoError(t, err)
+		require.NotContains(t, statistics, "3")
+		require.NotContains(t, statistics, "4")
+		require.Contains(t, statistics, "5")
+	})
+
+	t.Run("line is not among merged events", func(t *testing.T) {
+		service := newService(`[{"type": "void", "source": "finish", "line": 3, "issuedBy": "Chief of timing", "reason": "Double read"}]`)
+		_, err := service.ParseCorrections()
+		require.NoError(t, err)
+		_, err = service.ParseEvents(&config.Config{Laps: 1, StartDelta: config.Duration(90 * time.Second)})
+		require.EqualError(t, err, "invalid correction: line 3 of finish is not among merged events")
+	})
+}
@@ -0,0 +1,172 @@
+package services
+
+import (
+	"fmt"
+	"slices"
+	"system_prototype_for_biathlon_competitions/internal/clock"
+	"system_prototype_for_biathlon_competitions/internal/config"
+	"system_prototype_for_biathlon_competitions/internal/messages"
+	"time"
+)
+
+// Handling of events violating the rule (time of event N+1) >= (time of event N):
+// reject  - processing stops with the number of the offending line (default)
+// reorder - events are held in a buffer for ReorderWindow and released in time order;
+//           an event later than the window is rejected
+// warn    - the event is processed where it is and a diagnostic is recorded
+//
+// Format of the diagnostics summary:
+// line N[ (source:line)]: [event] is <skew> earlier than previous event, <action>
+//
+// Example:
+// line 14: [10:00:01.744] 4 1 is 00:00:00.256 earlier than previous event, reordered
+
+// Действия, выполненные с событием, нарушившим порядок времени.
+const (
+	OrderActionReordered = "reordered" // Событие переставлено на своё место по времени
+	OrderActionAccepted  = "accepted"  // Событие обработано в порядке поступления
+)
+
+// OrderDiagnostic представляет собой запись о событии, поступившем раньше по времени, чем предыдущее.
+type OrderDiagnostic struct {
+	Line   int           // Номер строки в исходном файле событий
+	Origin string        // Источник и строка события при объединении нескольких источников
+	Event  string        // Строка события
+	Skew   time.Duration // Насколько событие раньше самого позднего из предыдущих событий
+	Action string        // Выполненное действие
+}
+
+// eventLine представляет собой строку файла событий вместе с её номером в исходном файле.
+type eventLine struct {
+	number int
+	text   string
+}
+
+// orderedEvent представляет собой разобранное событие, ожидающее обработки.
+type orderedEvent struct {
+	eventLine
+	time  time.Time
+	event *Event
+}
+
+// eventOrderer проверяет соблюдение порядка времени событий и при необходимости
+// переупорядочивает события в пределах окна.
+type eventOrderer struct {
+	mode        string
+	window      time.Duration
+	latest      time.Time
+	buffer      []*orderedEvent
+	diagnostics []*OrderDiagnostic
+}
+
+// newEventOrderer создаёт проверку порядка событий по режиму из конфигурации.
+// Время событий не может быть раньше начала суток гонки raceDay.
+func newEventOrderer(cfg *config.Config, raceDay time.Time) (*eventOrderer, error) {
+	if err := cfg.Validate("eventOrder", "reorderWindow"); err != nil {
+		return nil, err
+	}
+	orderer := &eventOrderer{mode: cfg.EventOrder, latest: raceDay}
+	if cfg.EventOrder == config.EventOrderReorder {
+		orderer.window = time.Duration(cfg.ReorderWindow)
+	}
+
+	return orderer, nil
+}
+
+// push принимает очередное событие и возвращает события, готовые к обработке, в порядке времени.
+func (o *eventOrderer) push(event *orderedEvent) ([]*orderedEvent, error) {
+	if !event.time.Before(o.latest) {
+		o.latest = event.time
+		if o.mode != config.EventOrderReorder {
+			return []*orderedEvent{event}, nil
+		}
+		o.buffer = append(o.buffer, event)
+		return o.release(o.latest), nil
+	}
+
+	skew := o.latest.Sub(event.time)
+	switch o.mode {
+	case config.EventOrderWarn:
+		o.diagnose(event, skew, OrderActionAccepted)
+		return []*orderedEvent{event}, nil
+	case config.EventOrderReorder:
+		if skew > o.window {
+			return nil, o.lineError(event, fmt.Sprintf("time within reorder window %s of previous event %s", formatSkew(o.window), o.latest.Format(clock.TimeLayout)))
+		}
+		o.diagnose(event, skew, OrderActionReordered)
+		index, _ := slices.BinarySearchFunc(o.buffer, event.time, func(buffered *orderedEvent, t time.Time) int {
+			if buffered.time.After(t) {
+				return 1
+			}
+			return -1
+		})
+		o.buffer = slices.Insert(o.buffer, index, event)
+		return o.release(o.latest), nil
+	default:
+		return nil, o.lineError(event, "time not earlier than previous event "+o.latest.Format(clock.TimeLayout))
+	}
+}
+
+// lineError возвращает ошибку события, нарушившего порядок времени, с ожидаемым временем expected.
+func (o *eventOrderer) lineError(event *orderedEvent, expected string) *LineError {
+	found := fmt.Sprintf("%s (%s earlier)", event.time.Format(clock.TimeLayout), formatSkew(o.latest.Sub(event.time)))
+	return event.event.lineError(event.event.tokens[0].column+1, expected, found)
+}
+
+// release возвращает из буфера события, которые не могут быть опережены событиями,
+// поступившими позже: их время не позже now - window.
+func (o *eventOrderer) release(now time.Time) []*orderedEvent {
+	released := 0
+	for released < len(o.buffer) && now.Sub(o.buffer[released].time) >= o.window {
+		released++
+	}
+	ready := o.buffer[:released:released]
+	o.buffer = o.buffer[released:]
+
+	return ready
+}
+
+// flush возвращает все оставшиеся в буфере события.
+func (o *eventOrderer) flush() []*orderedEvent {
+	ready := o.buffer
+	o.buffer = nil
+
+	return ready
+}
+
+// diagnose записывает диагностику события, нарушившего порядок времени.
+func (o *eventOrderer) diagnose(event *orderedEvent, skew time.Duration, action string) {
+	o.diagnostics = append(o.diagnostics, &OrderDiagnostic{
+		Line:   event.number,
+		Event:  event.text,
+		Skew:   skew,
+		Action: action,
+	})
+}
+
+// OrderDiagnostics возвращает записи о событиях, нарушивших порядок времени, обработанных методом ParseEvents.
+func (s *ParseService) OrderDiagnostics() []*OrderDiagnostic {
+	return s.orderDiagnostics
+}
+
+// orderActionKeys задаёт ключи сообщений выполненных действий с событиями, нарушившими порядок времени.
+var orderActionKeys = map[string]string{
+	OrderActionReordered: messages.DiagnosticReordered,
+	OrderActionAccepted:  messages.DiagnosticAccepted,
+}
+
+// FormatOrderDiagnostic возвращает строковое представление записи о нарушении порядка событий
+// на языке каталога catalog.
+func FormatOrderDiagnostic(diagnostic *OrderDiagnostic, catalog *messages.Catalog) string {
+	line := catalog.Format(messages.DiagnosticLine, diagnostic.Line)
+	if diagnostic.Origin != "" {
+		line = catalog.Format(messages.DiagnosticLineOrigin, diagnostic.Line, diagnostic.Origin)
+	}
+
+	return catalog.Format(messages.DiagnosticOrder, line, diagnostic.Event, formatSkew(diagnostic.Skew), catalog.Format(orderActionKeys[diagnostic.Action]))
+}
+
+// formatSkew возвращает строковое представление отставания события.
+func formatSkew(skew time.Duration) string {
+	return formatDuration(skew, nil)
+}
@@ -0,0 +1,80 @@
+package services_test
+
+import (
+	"system_prototype_for_biathlon_competitions/internal/config"
+	"system_prototype_for_biathlon_competitions/internal/entities"
+	"system_prototype_for_biathlon_competitions/internal/services"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestParseEventsOrder тестирует обработку событий, нарушающих порядок времени.
+func TestParseEventsOrder(t *testing.T) {
+	events := "[09:00:00.500] 1 2\n" +
+		"[09:00:00.000] 1 1\n" +
+		"[09:01:00.000] 2 1 10:00:00.000\n" +
+		"[09:01:00.000] 2 2 10:01:30.000\n"
+
+	tests := []struct {
+		name        string
+		config      *config.Config
+		err         string
+		diagnostics []string
+	}{
+		{
+			name:   "reject by default",
+			config: &config.Config{Laps: 1, StartDelta: config.Duration(90 * time.Second)},
+			err:    "line 2, column 2: expected time not earlier than previous event 09:00:00.500, found 09:00:00.000 (00:00:00.500 earlier)",
+		},
+		{
+			name:        "accept with warning",
+			config:      &config.Config{Laps: 1, StartDelta: config.Duration(90 * time.Second), EventOrder: config.EventOrderWarn},
+			diagnostics: []string{"line 2: [09:00:00.000] 1 1 is 00:00:00.500 earlier than previous event, accepted"},
+		},
+		{
+			name:        "reorder within window",
+			config:      &config.Config{Laps: 1, StartDelta: config.Duration(90 * time.Second), EventOrder: config.EventOrderReorder, ReorderWindow: config.Duration(time.Second)},
+			diagnostics: []string{"line 2: [09:00:00.000] 1 1 is 00:00:00.500 earlier than previous event, reordered"},
+		},
+		{
+			name:   "reorder beyond window",
+			config: &config.Config{Laps: 1, StartDelta: config.Duration(90 * time.Second), EventOrder: config.EventOrderReorder, ReorderWindow: config.Duration(100 * time.Millisecond)},
+			err:    "line 2, column 2: expected time within reorder window 00:00:00.100 of previous event 09:00:00.500",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := services.NewParseService(&entities.Files{EventsFile: openTempFile(t, "events", events)})
+
+			statistics, err := service.ParseEvents(tt.config)
+			if tt.err != "" {
+				require.ErrorContains(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, statistics, 2)
+
+			var diagnostics []string
+			for _, diagnostic := range service.OrderDiagnostics() {
+				diagnostics = append(diagnostics, services.FormatOrderDiagnostic(diagnostic, nil))
+			}
+			require.Equal(t, tt.diagnostics, diagnostics)
+		})
+	}
+}
+
+// TestParseEventsReorderBeforeProcessing тестирует, что переупорядоченные события обрабатываются по времени.
+func TestParseEventsReorderBeforeProcessing(t *testing.T) {
+	events := "[09:00:00.500] 2 1 10:00:00.000\n" +
+		"[09:00:00.000] 1 1\n" +
+		"[10:00:01.000] 4 1\n"
+	service := services.NewParseService(&entities.Files{EventsFile: openTempFile(t, "events", events)})
+
+	statistics, err := service.ParseEvents(&config.Config{Laps: 1, StartDelta: config.Duration(90 * time.Second), EventOrder: config.EventOrderReorder, ReorderWindow: config.Duration(time.Second)})
+	require.NoError(t, err)
+	require.False(t, statistics.GetStatistic("1").RequiredStart.IsZero())
+	require.False(t, statistics.GetStatistic("1").IsDisqualified)
+}
@@ -2,21 +2,30 @@ package services
 
 import (
 	"bufio"
-	"encoding/json"
+	"cmp"
+	"errors"
 	"fmt"
 	"io"
+	"slices"
 	"strings"
+	"system_prototype_for_biathlon_competitions/internal/clock"
 	"system_prototype_for_biathlon_competitions/internal/config"
 	"system_prototype_for_biathlon_competitions/internal/entities"
+	"system_prototype_for_biathlon_competitions/internal/messages"
+	"system_prototype_for_biathlon_competitions/internal/storage"
 	"time"
 )
 
 // Common format for events:
 // [time] eventID competitorID extraParams
 //
+// Time is either a time of day (15:04:05.000) on the race date from the config,
+// or a time with an explicit date (2006-01-02T15:04:05.000). A time of day more than
+// 12 hours earlier than the previous event is treated as the next day (crossing midnight).
+//
 // Incoming events
 // EventID | extraParams | Comments
-// 1 	   |             | The competitor registered
+// 1 	   | [category]  | The competitor registered
 // 2       | startTime   | The start time was set by a draw
 // 3       |             | The competitor is on the start line
 // 4       |             | The competitor has started
@@ -27,15 +36,32 @@ import (
 // 9 	   | 			 | The competitor left the penalty laps
 // 10 	   | 			 | The competitor ended the main lap
 // 11 	   | comment 	 | The competitor can`t continue
+// 12 	   | 			 | The competitor is lapped
+// 13 	   | rule 		 | The competitor is disqualified for a rule violation
 //
 // Outgoing events
 // EventID | extraParams | Comments
 // 32 	   | 			 | The competitor is disqualified
 // 33 	   | 			 | The competitor has finished
+// 34 	   | 			 | The competitor did not finish
+// 35 	   | 			 | The competitor is out of the race as lapped
+// 36 	   | rule 		 | The competitor is out of the race as disqualified
+// 37 	   | 			 | The protest window after the finish of the competitor is closed
+// 38 	   | 			 | The competitor is still on the course at the cutoff time
 
 // ParseService представляет сервис для обработки и парсинга файлов.
 type ParseService struct {
-	files *entities.Files
+	files            *entities.Files
+	events           []string
+	corrections      []*entities.Correction
+	catalog          *messages.Catalog
+	handlers         *EventHandlerRegistry
+	statistics       storage.StatisticRepository
+	origins          []string
+	duplicates       []*DuplicateEvent
+	orderDiagnostics []*OrderDiagnostic
+	lenient          bool
+	lineErrors       []*LineError
 }
 
 // TimeSet представляет собой структуру, содержащую информацию о времени.
@@ -48,146 +74,381 @@ func NewParseService(files *entities.Files) *ParseService {
 	return &ParseService{files: files}
 }
 
-// ParseConfig считывает и парсит конфигурационный файл в формате JSON.
+// SetEventHandlers задаёт обработчики входящих событий. По умолчанию используются DefaultEventHandlers.
+func (s *ParseService) SetEventHandlers(handlers *EventHandlerRegistry) {
+	s.handlers = handlers
+}
+
+// SetStatisticRepository задаёт хранилище, в которое обработчики событий записывают статистику
+// участников. По умолчанию каждый разбор событий ведёт статистику в новом хранилище в памяти.
+func (s *ParseService) SetStatisticRepository(statistics storage.StatisticRepository) {
+	s.statistics = statistics
+}
+
+// SetMessages задаёт каталог сообщений журнала событий. По умолчанию журнал ведётся на английском языке.
+func (s *ParseService) SetMessages(catalog *messages.Catalog) {
+	s.catalog = catalog
+}
+
+// SetLenient включает мягкий режим разбора событий: строки с ошибками пропускаются,
+// а ошибки собираются за один проход и доступны через LineErrors.
+func (s *ParseService) SetLenient(lenient bool) {
+	s.lenient = lenient
+}
+
+// ParseConfig считывает и парсит конфигурационный файл в формате JSON, YAML или TOML
+// (по расширению файла или содержимому), заполняет необязательные настройки значениями
+// по умолчанию и проверяет значения полей (см. config.LoadFile).
 func (s *ParseService) ParseConfig() (*config.Config, error) {
-	config := &config.Config{}
-	rd := bufio.NewReader(s.files.ConfigFile)
-	if err := json.NewDecoder(rd).Decode(config); err != nil {
-		return nil, fmt.Errorf("failed to decode json: %w", err)
+	cfg, err := config.LoadFile(s.files.ConfigFile.Name(), s.files.ConfigFile)
+	if err != nil {
+		var fieldErrors config.FieldErrors
+		if errors.As(err, &fieldErrors) {
+			return nil, fmt.Errorf("invalid config: %w", err)
+		}
+		return nil, err
 	}
 
-	return config, nil
+	return cfg, nil
 }
 
-// ParseEvents обрабатывает события из файла событий и возвращает статистику участников.
-func (s *ParseService) ParseEvents(config *config.Config) (map[string]*entities.Statistic, error) {
-	statistics := make(map[string]*entities.Statistic)
-	reader := bufio.NewReader(s.files.EventsFile)
-	for {
-		line, err := reader.ReadString('\n')
+// ParseEvents обрабатывает события из файла событий (или объединённый поток событий
+// нескольких источников) и возвращает хранилище статистики участников.
+// Исправления жюри, аннулирующие или заменяющие события, применяются до обработки.
+// Сроки (закрытие стартовых интервалов) отслеживаются по симулированным часам,
+// которые переводятся на время каждого очередного события. События, нарушающие
+// порядок времени, обрабатываются по режиму EventOrder из конфигурации.
+// Пустые строки и комментарии пропускаются; ошибка в строке события возвращается как *LineError
+// (в мягком режиме строка пропускается, а ошибка запоминается).
+func (s *ParseService) ParseEvents(config *config.Config) (storage.StatisticRepository, error) {
+	processor, err := newEventProcessor(config, s.handlers, s.statisticRepository(), s.catalog, s.lenient)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(s.files.EventSources) > 0 {
+		merged, duplicates, err := mergeEventSources(s.files.EventSources, config, processor.report)
 		if err != nil {
-			if err == io.EOF {
-				break
-			}
-			return nil, fmt.Errorf("failed to read event: %w", err)
+			return nil, err
 		}
-
-		line = strings.TrimSuffix(line, "\n")
-		partition := strings.Split(line, " ")
-		if len(partition) < 3 {
-			return nil, fmt.Errorf("ivalid incoming events: insufficient number of parameters")
+		for _, event := range merged {
+			s.events = append(s.events, event.text)
+			s.origins = append(s.origins, event.origin)
 		}
-
-		logTime := partition[0]
-		eventID := partition[1]
-		competitorID := partition[2]
-		actualTime, err := time.Parse("15:04:05.000", strings.Trim(logTime, "[]"))
+		s.duplicates = duplicates
+	} else {
+		events, err := readEventLines(s.files.EventsFile)
 		if err != nil {
-			return nil, fmt.Errorf("ivalid incoming events: failed to parse time: %w", err)
+			return nil, err
 		}
+		s.events = events
+	}
 
-		switch eventID {
-		case "1":
-			if statistics[competitorID] != nil {
-				return nil, fmt.Errorf("ivalid incoming events: competitor has already been registered")
+	lines, err := applyEventCorrections(s.events, s.origins, s.corrections)
+	if err != nil {
+		return nil, err
+	}
+
+	raceClock := clock.NewSimulatedClock(processor.last)
+	process := func(events []*orderedEvent) error {
+		for _, event := range events {
+			raceClock.Set(event.time)
+			processor.advance(raceClock.Now())
+			if err := processor.process(event); err != nil {
+				return err
 			}
-			statistics[competitorID] = &entities.Statistic{}
-			statistics[competitorID].CompetitorID = competitorID
+		}
+		return nil
+	}
+	for _, line := range lines {
+		ready, err := processor.push(line)
+		if err != nil {
+			return nil, err
+		}
+		if err := process(ready); err != nil {
+			return nil, err
+		}
+	}
+	if err := process(processor.orderer.flush()); err != nil {
+		return nil, err
+	}
+	processor.finish()
+	s.orderDiagnostics = processor.orderer.diagnostics
+	for _, diagnostic := range s.orderDiagnostics {
+		if s.origins != nil {
+			diagnostic.Origin = s.origins[diagnostic.Line-1]
+		}
+	}
+	s.lineErrors = processor.lineErrors
+	for _, lineErr := range s.lineErrors {
+		if s.origins != nil && lineErr.Line > 0 {
+			lineErr.Origin = s.origins[lineErr.Line-1]
+		}
+	}
+
+	return processor.statistics(), nil
+}
 
-			fmt.Printf("%s The competitor(%s) registered\n", logTime, competitorID)
-		case "2":
-			if len(partition) < 4 {
-				return nil, fmt.Errorf("ivalid incoming events: insufficient number of parameters")
+// ParseEventsLive обрабатывает события по мере их поступления в файл событий (например, из канала
+// или стандартного ввода) до конца потока. Сроки отслеживаются по часам raceClock, которые
+// опрашиваются с периодом tick и перед каждым поступившим событием, поэтому исходящие события
+// (например, 32) выводятся в момент наступления срока, даже если новых входящих событий нет. Время суток часов переносится
+// на сутки последнего события с учётом перехода через полночь.
+func (s *ParseService) ParseEventsLive(config *config.Config, raceClock clock.Clock, tick time.Duration) (storage.StatisticRepository, error) {
+	if len(s.files.EventSources) > 0 {
+		return nil, fmt.Errorf("merging event sources is not supported in live mode")
+	}
+	for _, correction := range s.corrections {
+		if correction.Type == entities.CorrectionVoid || correction.Type == entities.CorrectionReplace {
+			return nil, fmt.Errorf("event corrections are not supported in live mode")
+		}
+	}
+
+	processor, err := newEventProcessor(config, s.handlers, s.statisticRepository(), s.catalog, s.lenient)
+	if err != nil {
+		return nil, err
+	}
+
+	lines := make(chan string)
+	readErr := make(chan error, 1)
+	go func() {
+		defer close(lines)
+		reader := bufio.NewReader(s.files.EventsFile)
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil && err != io.EOF {
+				readErr <- fmt.Errorf("failed to read event: %w", err)
+				return
 			}
-			requiredStartStr := partition[3]
-			requiredStart, err := time.Parse("15:04:05.000", requiredStartStr)
-			if err != nil {
-				return nil, fmt.Errorf("ivalid incoming events: failed to parse log time: %w", err)
+			line = strings.TrimSuffix(line, "\n")
+			if line != "" || err == nil {
+				lines <- line
 			}
-			statistics[competitorID].RequiredStart = requiredStart
-
-			fmt.Printf("%s The start time for the competitor(%s) was set by a draw to %s\n", logTime, competitorID, requiredStartStr)
-		case "3":
-			fmt.Printf("%s The competitor(%s) is on the start line\n", logTime, competitorID)
-		case "4":
-			statistics[competitorID].ActualStart = actualTime
-			deltaTime, err := time.Parse("03:04:05", config.StartDelta)
-			if err != nil {
-				return nil, fmt.Errorf("ivalid incoming events: failed to parse delta time in config: %w", err)
+			if err == io.EOF {
+				return
 			}
+		}
+	}()
 
-			startDelta := formatTimeToDuration(deltaTime)
-			timeSet := &TimeSet{
-				ActualTime: actualTime,
-				StartDelta: startDelta,
+	process := func(events []*orderedEvent) error {
+		for _, event := range events {
+			processor.advance(event.time)
+			if err := processor.process(event); err != nil {
+				return err
 			}
-			DisqualifiedCheck(statistics, timeSet)
+		}
+		return nil
+	}
 
-			fmt.Printf("%s The competitor(%s) has started\n", logTime, competitorID)
-		case "5":
-			if len(partition) < 4 {
-				return nil, fmt.Errorf("ivalid incoming events: insufficient number of parameters")
+	ticker := time.NewTicker(tick)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			now := clock.Resolve(raceClock.Now(), processor.last)
+			if err := process(processor.orderer.release(now)); err != nil {
+				return nil, err
 			}
-			firingRange := partition[3]
-			statistics[competitorID].NumberOfFiringRangeVisited++
-			statistics[competitorID].NumberOfPenaltyLaps = 5
-
-			fmt.Printf("%s The competitor(%s) is on the firing range(%s)\n", logTime, competitorID, firingRange)
-		case "6":
-			if len(partition) < 4 {
-				return nil, fmt.Errorf("ivalid incoming events: insufficient number of parameters")
+			processor.advance(now)
+		case line, ok := <-lines:
+			if !ok {
+				select {
+				case err := <-readErr:
+					return nil, err
+				default:
+				}
+				if err := process(processor.orderer.flush()); err != nil {
+					return nil, err
+				}
+				processor.finish()
+				s.orderDiagnostics = processor.orderer.diagnostics
+				s.lineErrors = processor.lineErrors
+				return processor.statistics(), nil
 			}
-			target := partition[3]
-			statistics[competitorID].NumberOfHits++
-			statistics[competitorID].NumberOfPenaltyLaps--
-
-			fmt.Printf("%s The target(%s) has been hit by competitor(%s)\n", logTime, target, competitorID)
-		case "7":
-			fmt.Printf("%s The competitor(%s) left the firing range\n", logTime, competitorID)
-		case "8":
-			statistics[competitorID].StartPenaltyLaps = actualTime
-
-			fmt.Printf("%s The competitor(%s) entered the penalty laps\n", logTime, competitorID)
-		case "9":
-			startPenaltyLaps := statistics[competitorID].StartPenaltyLaps
-			statistics[competitorID].NumberOfCompletionPenaltyLaps += statistics[competitorID].NumberOfPenaltyLaps
-			statistics[competitorID].TotalTimeOfPenaltyLaps += actualTime.Sub(startPenaltyLaps)
-
-			fmt.Printf("%s The competitor(%s) left the penalty laps\n", logTime, competitorID)
-		case "10":
-			statistics[competitorID].NumberOfEndedLaps++
-			statistics[competitorID].TimeOfLapsCompletion = append(statistics[competitorID].TimeOfLapsCompletion, actualTime)
-			if statistics[competitorID].NumberOfEndedLaps != config.Laps {
-				fmt.Printf("%s The competitor(%s) ended the main lap\n", logTime, competitorID)
-				continue
+			processor.advance(clock.Resolve(raceClock.Now(), processor.last))
+			s.events = append(s.events, line)
+			ready, err := processor.push(eventLine{number: len(s.events), text: line})
+			if err != nil {
+				return nil, err
+			}
+			if err := process(ready); err != nil {
+				return nil, err
 			}
-			statistics[competitorID].IsFinished = true
-			statistics[competitorID].ActualFinish = actualTime
+		}
+	}
+}
+
+// eventProcessor обрабатывает входящие события и отслеживает сроки по часам гонки.
+type eventProcessor struct {
+	ctx        *EventContext
+	handlers   *EventHandlerRegistry
+	timers     *clock.Timers
+	orderer    *eventOrderer
+	last       time.Time // Время самого позднего из событий (до первого события — начало суток гонки)
+	lenient    bool
+	lineErrors []*LineError
+}
+
+// statisticRepository возвращает хранилище статистики участников для очередного разбора событий.
+func (s *ParseService) statisticRepository() storage.StatisticRepository {
+	if s.statistics != nil {
+		return s.statistics
+	}
 
-			fmt.Printf("%s The competitor(%s) has finished\n", logTime, competitorID)
-		case "11":
-			comment := strings.Join(partition[3:], " ")
+	return storage.MemoryStatistics{}
+}
 
-			fmt.Printf("%s The competitor(%s) can`t continue: %s\n", logTime, competitorID, comment)
+// newEventProcessor создаёт обработчик событий для гонки с конфигурацией config,
+// передающий события обработчикам из handlers, записывающий статистику участников в statistics и выводящий журнал событий с сообщениями из каталога catalog.
+// В мягком режиме lenient ошибки в строках событий запоминаются, а строки пропускаются.
+func newEventProcessor(config *config.Config, handlers *EventHandlerRegistry, statistics storage.StatisticRepository, catalog *messages.Catalog, lenient bool) (*eventProcessor, error) {
+	if err := config.Validate("startDelta"); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+	raceDay := config.Date.Day()
+	orderer, err := newEventOrderer(config, raceDay)
+	if err != nil {
+		return nil, err
+	}
+	if handlers == nil {
+		handlers = DefaultEventHandlers()
+	}
+
+	timers := clock.NewTimers()
+	return &eventProcessor{
+		ctx: &EventContext{
+			Config:     config,
+			Statistics: statistics,
+			StartDelta: time.Duration(config.StartDelta),
+			timers:     timers,
+			catalog:    catalog,
+			withdrawn:  make(map[string]bool),
+		},
+		handlers: handlers,
+		timers:   timers,
+		orderer:  orderer,
+		last:     raceDay,
+		lenient:  lenient,
+	}, nil
+}
+
+// report возвращает ошибку err, а в мягком режиме запоминает ошибку в строке события
+// и возвращает nil, чтобы обработка продолжилась со следующей строки.
+func (p *eventProcessor) report(err error) error {
+	var lineErr *LineError
+	if !p.lenient || !errors.As(err, &lineErr) {
+		return err
+	}
+	p.lineErrors = append(p.lineErrors, lineErr)
+
+	return nil
+}
+
+// push разбирает строку события и передаёт событие на проверку порядка времени. Время суток
+// переносится на сутки предыдущего события с учётом перехода через полночь. Пустые строки
+// и комментарии пропускаются. Возвращает события, готовые к обработке, в порядке их обработки.
+func (p *eventProcessor) push(line eventLine) ([]*orderedEvent, error) {
+	if isSkippedEventLine(line.text) {
+		return nil, nil
+	}
+	event, err := parseEventLine(line.number, line.text, p.last)
+	if err != nil {
+		return nil, p.report(err)
+	}
+	if event.Time.After(p.last) {
+		p.last = event.Time
+	}
+
+	ready, err := p.orderer.push(&orderedEvent{eventLine: line, time: event.Time, event: event})
+	if err != nil {
+		return nil, p.report(err)
+	}
+
+	return ready, nil
+}
+
+// advance выполняет все таймеры, срок которых наступил к моменту now по часам гонки.
+func (p *eventProcessor) advance(now time.Time) {
+	p.timers.Fire(now)
+}
+
+// finish выполняет после окончания потока событий таймеры, срок которых не позже последнего события.
+// Сроки после последнего события не наступили по часам гонки и не выполняются: оборванная лента
+// не дисквалифицирует участников, стартовый интервал которых ещё не закрылся.
+func (p *eventProcessor) finish() {
+	p.advance(p.last.Add(time.Nanosecond))
+}
+
+// statistics возвращает хранилище статистики участников, накопленной обработчиками событий.
+func (p *eventProcessor) statistics() storage.StatisticRepository {
+	return p.ctx.Statistics
+}
+
+// process передаёт событие назначенному обработчику. События без обработчика пропускаются,
+// события участника, который уже финишировал или сошёл с дистанции, отклоняются.
+// Ошибка обработчика дополняется номером и текстом строки события.
+func (p *eventProcessor) process(ordered *orderedEvent) error {
+	handler, ok := p.handlers.Handler(ordered.event.ID)
+	if !ok {
+		return nil
+	}
+	err := p.ctx.checkActive(ordered.event)
+	if err == nil {
+		err = handler(p.ctx, ordered.event)
+	}
+	if err != nil {
+		var lineErr *LineError
+		if !errors.As(err, &lineErr) {
+			err = &LineError{Line: ordered.number, Text: ordered.event.text, Err: err}
 		}
+		return p.report(err)
 	}
 
-	return statistics, nil
+	return nil
+}
+
+// EventIDs возвращает идентификаторы встроенных входящих и исходящих событий, выводимых в журнал событий.
+func EventIDs() []string {
+	return append(DefaultEventHandlers().EventIDs(), "32", "33", "34", "35", "36", "37", "38")
+}
+
+// LineErrors возвращает ошибки в строках событий, пропущенных методом ParseEvents в мягком режиме.
+func (s *ParseService) LineErrors() []*LineError {
+	return s.lineErrors
+}
+
+// Events возвращает исходные строки входящих событий, прочитанные методом ParseEvents.
+func (s *ParseService) Events() []string {
+	return s.events
 }
 
 // DisqualifiedCheck проверяет, был ли участник дисквалифицирован на основе
-// предоставленной статистики и текущего времени.
-func DisqualifiedCheck(statistics map[string]*entities.Statistic, timeSet *TimeSet) {
-	for competitorID, statistic := range statistics {
+// предоставленной статистики и текущего времени. Проверка выполняется перед каждым событием:
+// участник, не стартовавший до закрытия своего стартового интервала, дисквалифицируется
+// временем закрытия интервала. Возвращает дисквалифицированных проверкой участников
+// в порядке времени закрытия интервалов для вывода исходящего события 32.
+func DisqualifiedCheck(statistics storage.StatisticRepository, timeSet *TimeSet) []*entities.Statistic {
+	var disqualified []*entities.Statistic
+	for _, statistic := range statistics.ListStatistics() {
 		requiredStart := statistic.RequiredStart
 
 		if requiredStart.IsZero() || statistic.IsDisqualified || !statistic.ActualStart.IsZero() {
 			continue
 		}
-		if timeSet.ActualTime.After(requiredStart.Add(timeSet.StartDelta)) {
-			actualTimeStr := timeSet.ActualTime.Format("15:04:05.000")
-			fmt.Printf("%s The competitor(%s) is disqualified\n", actualTimeStr, competitorID)
-			statistics[competitorID].IsDisqualified = true
+		windowClosing := requiredStart.Add(timeSet.StartDelta)
+		if timeSet.ActualTime.After(windowClosing) {
+			statistic.IsDisqualified = true
+			statistic.DisqualifiedAt = windowClosing
+			disqualified = append(disqualified, statistic)
 		}
 	}
+
+	slices.SortFunc(disqualified, func(a, b *entities.Statistic) int {
+		if c := a.DisqualifiedAt.Compare(b.DisqualifiedAt); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.CompetitorID, b.CompetitorID)
+	})
+
+	return disqualified
 }
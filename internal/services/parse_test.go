@@ -1,11 +1,17 @@
 package services_test
 
 import (
+	"io"
+	"os"
 	"testing"
 	"time"
 
+	"system_prototype_for_biathlon_competitions/internal/clock"
+	"system_prototype_for_biathlon_competitions/internal/config"
 	"system_prototype_for_biathlon_competitions/internal/entities"
+	"system_prototype_for_biathlon_competitions/internal/messages"
 	"system_prototype_for_biathlon_competitions/internal/services"
+	"system_prototype_for_biathlon_competitions/internal/storage"
 
 	"github.com/stretchr/testify/require"
 )
@@ -13,7 +19,7 @@ import (
 // TestDisqualifiedCheck тестирует функцию DisqualifiedCheck.
 func TestDisqualifiedCheck(t *testing.T) {
 	t.Run("competitor is disqualified", func(t *testing.T) {
-		statistics := map[string]*entities.Statistic{
+		statistics := storage.MemoryStatistics{
 			"1": {
 				CompetitorID:   "1",
 				RequiredStart:  time.Date(2023, 10, 1, 10, 0, 0, 0, time.UTC),
@@ -28,11 +34,11 @@ func TestDisqualifiedCheck(t *testing.T) {
 
 		services.DisqualifiedCheck(statistics, timeSet)
 
-		require.True(t, statistics["1"].IsDisqualified)
+		require.True(t, statistics.GetStatistic("1").IsDisqualified)
 	})
 
 	t.Run("start on time", func(t *testing.T) {
-		statistics := map[string]*entities.Statistic{
+		statistics := storage.MemoryStatistics{
 			"1": {
 				CompetitorID:   "1",
 				RequiredStart:  time.Date(2023, 10, 1, 10, 0, 0, 0, time.UTC),
@@ -47,11 +53,11 @@ func TestDisqualifiedCheck(t *testing.T) {
 
 		services.DisqualifiedCheck(statistics, timeSet)
 
-		require.False(t, statistics["1"].IsDisqualified)
+		require.False(t, statistics.GetStatistic("1").IsDisqualified)
 	})
 
 	t.Run("competitor is already disqualified", func(t *testing.T) {
-		statistics := map[string]*entities.Statistic{
+		statistics := storage.MemoryStatistics{
 			"1": {
 				CompetitorID:   "1",
 				RequiredStart:  time.Date(2023, 10, 1, 10, 0, 0, 0, time.UTC),
@@ -66,11 +72,11 @@ func TestDisqualifiedCheck(t *testing.T) {
 
 		services.DisqualifiedCheck(statistics, timeSet)
 
-		require.True(t, statistics["1"].IsDisqualified)
+		require.True(t, statistics.GetStatistic("1").IsDisqualified)
 	})
 
 	t.Run("actual start time is already set", func(t *testing.T) {
-		statistics := map[string]*entities.Statistic{
+		statistics := storage.MemoryStatistics{
 			"1": {
 				CompetitorID:   "1",
 				RequiredStart:  time.Date(2023, 10, 1, 10, 0, 0, 0, time.UTC),
@@ -85,11 +91,11 @@ func TestDisqualifiedCheck(t *testing.T) {
 
 		services.DisqualifiedCheck(statistics, timeSet)
 
-		require.False(t, statistics["1"].IsDisqualified)
+		require.False(t, statistics.GetStatistic("1").IsDisqualified)
 	})
 
 	t.Run("required start time is zero", func(t *testing.T) {
-		statistics := map[string]*entities.Statistic{
+		statistics := storage.MemoryStatistics{
 			"1": {
 				CompetitorID:   "1",
 				RequiredStart:  time.Time{},
@@ -104,6 +110,185 @@ func TestDisqualifiedCheck(t *testing.T) {
 
 		services.DisqualifiedCheck(statistics, timeSet)
 
-		require.False(t, statistics["1"].IsDisqualified)
+		require.False(t, statistics.GetStatistic("1").IsDisqualified)
 	})
 }
+
+// TestParseEventsLateStart тестирует дисквалификацию за опоздание на старт при обработке событий.
+func TestParseEventsLateStart(t *testing.T) {
+	cfg := &config.Config{Laps: 1, StartDelta: config.Duration(90 * time.Second)}
+
+	t.Run("window closes before a later event", func(t *testing.T) {
+		events := "[09:00:00.000] 1 1\n" +
+			"[09:00:00.000] 1 2\n" +
+			"[09:01:00.000] 2 1 10:00:00.000\n" +
+			"[09:01:00.000] 2 2 10:01:30.000\n" +
+			"[10:00:01.000] 4 1\n" +
+			"[10:05:00.000] 10 1\n"
+		service := services.NewParseService(&entities.Files{EventsFile: openTempFile(t, "events", events)})
+
+		statistics, err := service.ParseEvents(cfg)
+		require.NoError(t, err)
+		require.False(t, statistics.GetStatistic("1").IsDisqualified)
+		require.True(t, statistics.GetStatistic("2").IsDisqualified)
+		require.Equal(t, time.Date(0, 1, 1, 10, 3, 0, 0, time.UTC), statistics.GetStatistic("2").DisqualifiedAt)
+	})
+
+	t.Run("last competitor never starts", func(t *testing.T) {
+		events := "[09:00:00.000] 1 1\n" +
+			"[09:00:00.000] 1 2\n" +
+			"[09:01:00.000] 2 1 09:30:00.000\n" +
+			"[09:01:00.000] 2 2 10:00:00.000\n" +
+			"[09:30:01.000] 4 1\n" +
+			"[10:05:00.000] 10 1\n"
+		service := services.NewParseService(&entities.Files{EventsFile: openTempFile(t, "events", events)})
+
+		statistics, err := service.ParseEvents(cfg)
+		require.NoError(t, err)
+		require.True(t, statistics.GetStatistic("2").IsDisqualified)
+		require.Equal(t, time.Date(0, 1, 1, 10, 1, 30, 0, time.UTC), statistics.GetStatistic("2").DisqualifiedAt)
+	})
+
+	t.Run("feed ends before the window closes", func(t *testing.T) {
+		events := "[09:00:00.000] 1 1\n" +
+			"[09:01:00.000] 2 1 10:00:00.000\n" +
+			"[10:00:00.000] 3 1\n"
+		service := services.NewParseService(&entities.Files{EventsFile: openTempFile(t, "events", events)})
+
+		statistics, err := service.ParseEvents(cfg)
+		require.NoError(t, err)
+		require.False(t, statistics.GetStatistic("1").IsDisqualified)
+		require.Equal(t, entities.StatusDNS, services.GetResultStatus(statistics.GetStatistic("1")))
+	})
+
+	t.Run("start after the window closed", func(t *testing.T) {
+		events := "[09:00:00.000] 1 1\n" +
+			"[09:01:00.000] 2 1 10:00:00.000\n" +
+			"[10:02:00.000] 4 1\n"
+		service := services.NewParseService(&entities.Files{EventsFile: openTempFile(t, "events", events)})
+
+		statistics, err := service.ParseEvents(cfg)
+		require.NoError(t, err)
+		require.True(t, statistics.GetStatistic("1").IsDisqualified)
+	})
+}
+
+// TestParseEventsAcrossMidnight тестирует обработку гонки, проходящей через полночь.
+func TestParseEventsAcrossMidnight(t *testing.T) {
+	events := "[23:50:00.000] 1 1\n" +
+		"[23:50:00.000] 1 2\n" +
+		"[23:51:00.000] 2 1 23:59:00.000\n" +
+		"[23:51:00.000] 2 2 00:01:00.000\n" +
+		"[23:59:01.000] 4 1\n" +
+		"[00:10:00.000] 10 1\n" +
+		"[2024-01-16T00:12:00.000] 3 2\n"
+	service := services.NewParseService(&entities.Files{EventsFile: openTempFile(t, "events", events)})
+
+	statistics, err := service.ParseEvents(&config.Config{Laps: 1, StartDelta: config.Duration(90 * time.Second), Date: config.Date(time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC))})
+	require.NoError(t, err)
+
+	raceDay := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	require.True(t, statistics.GetStatistic("1").IsFinished)
+	require.Equal(t, raceDay.AddDate(0, 0, 1).Add(10*time.Minute), statistics.GetStatistic("1").ActualFinish)
+	require.Equal(t, "00:11:00.000", services.GetTotalTime(statistics.GetStatistic("1"), nil))
+	require.Equal(t, raceDay.AddDate(0, 0, 1).Add(time.Minute), statistics.GetStatistic("2").RequiredStart)
+	require.True(t, statistics.GetStatistic("2").IsDisqualified)
+	require.Equal(t, raceDay.AddDate(0, 0, 1).Add(150*time.Second), statistics.GetStatistic("2").DisqualifiedAt)
+}
+
+// TestParseEventsLive тестирует срабатывание сроков по часам гонки без поступления новых событий.
+func TestParseEventsLive(t *testing.T) {
+	reader, writer, err := os.Pipe()
+	require.NoError(t, err)
+	defer reader.Close()
+
+	raceClock := clock.NewSimulatedClock(clock.Midnight.Add(9 * time.Hour))
+	service := services.NewParseService(&entities.Files{EventsFile: reader})
+
+	type result struct {
+		statistics storage.StatisticRepository
+		err        error
+	}
+	done := make(chan result)
+	go func() {
+		statistics, err := service.ParseEventsLive(&config.Config{Laps: 1, StartDelta: config.Duration(90 * time.Second)}, raceClock, time.Millisecond)
+		done <- result{statistics, err}
+	}()
+
+	_, err = writer.WriteString("[09:00:00.000] 1 1\n[09:01:00.000] 2 1 10:00:00.000\n")
+	require.NoError(t, err)
+
+	// Часы гонки ушли за конец стартового интервала, но событие старта доставлено с опозданием
+	// и помечено временем внутри интервала: участник дисквалифицируется по часам до обработки события.
+	raceClock.Set(clock.Midnight.Add(10*time.Hour + 5*time.Minute))
+	_, err = writer.WriteString("[10:00:10.000] 4 1\n")
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+
+	res := <-done
+	require.NoError(t, res.err)
+	require.True(t, res.statistics.GetStatistic("1").IsDisqualified)
+	require.Equal(t, clock.Midnight.Add(10*time.Hour+90*time.Second), res.statistics.GetStatistic("1").DisqualifiedAt)
+	require.Len(t, service.Events(), 3)
+}
+
+// captureStdout возвращает вывод функции f в стандартный поток вывода (журнал событий).
+func captureStdout(t *testing.T, f func()) string {
+	t.Helper()
+	reader, writer, err := os.Pipe()
+	require.NoError(t, err)
+	stdout := os.Stdout
+	os.Stdout = writer
+	defer func() { os.Stdout = stdout }()
+
+	output := make(chan string)
+	go func() {
+		data, _ := io.ReadAll(reader)
+		output <- string(data)
+	}()
+	f()
+	require.NoError(t, writer.Close())
+
+	return <-output
+}
+
+// TestParseEventsDeadlines тестирует исходящие события закрытия окна подачи протестов и лимита времени на трассе.
+func TestParseEventsDeadlines(t *testing.T) {
+	events := "[09:00:00.000] 1 1\n" +
+		"[09:00:00.000] 1 2\n" +
+		"[09:01:00.000] 2 1 10:00:00.000\n" +
+		"[09:01:00.000] 2 2 10:01:00.000\n" +
+		"[10:00:01.000] 4 1\n" +
+		"[10:01:01.000] 4 2\n" +
+		"[10:20:00.000] 10 1\n" +
+		"[10:40:00.000] 11 2 Broken ski\n"
+	cfg := &config.Config{
+		Laps:          1,
+		StartDelta:    config.Duration(90 * time.Second),
+		ProtestWindow: config.Duration(10 * time.Minute),
+		CutoffTime:    config.Duration(30 * time.Minute),
+	}
+
+	var err error
+	output := captureStdout(t, func() {
+		service := services.NewParseService(&entities.Files{EventsFile: openTempFile(t, "events", events)})
+		_, err = service.ParseEvents(cfg)
+	})
+	require.NoError(t, err)
+	require.Contains(t, output, "[10:20:00.000] The competitor(1) has finished\n"+
+		"[10:30:00.000] The protest window after the finish of the competitor(1) is closed\n"+
+		"[10:31:01.000] The competitor(2) is still on the course at the cutoff time\n"+
+		"[10:40:00.000] The competitor(2) can`t continue: Broken ski\n")
+	require.NotContains(t, output, "The competitor(1) is still on the course")
+}
+
+// TestEventMessages тестирует, что для каждого события есть сообщение журнала на каждом языке.
+func TestEventMessages(t *testing.T) {
+	for _, locale := range messages.Locales() {
+		catalog, err := messages.New(locale)
+		require.NoError(t, err)
+		for _, eventID := range services.EventIDs() {
+			require.True(t, catalog.Has(messages.EventKey(eventID)), "locale %s has no message for event %s", locale, eventID)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package services
+
+import (
+	"fmt"
+	"system_prototype_for_biathlon_competitions/internal/config"
+	"system_prototype_for_biathlon_competitions/internal/entities"
+	"time"
+)
+
+// Format of the penalty column in the "time" penalty mode:
+// {raw course time, +penalty time}
+//
+// Example:
+// [00:27:18.356] 2 [{00:12:38.243, 4.616}, {00:12:38.610, 4.614}] {00:25:18.356, +00:02:00.000} 8/10
+
+// targetsPerVisit задаёт количество мишеней (и выстрелов) на одном посещении огневого рубежа.
+const targetsPerVisit = 5
+
+// IsPenaltyTimeMode сообщает, начисляется ли штраф за промахи временем, а не штрафными кругами.
+func IsPenaltyTimeMode(cfg *config.Config) bool {
+	return cfg != nil && cfg.PenaltyMode == config.PenaltyModeTime
+}
+
+// GetNumberOfMisses возвращает количество промахов участника по данным стрельбы.
+func GetNumberOfMisses(statistic *entities.Statistic) int {
+	return targetsPerVisit*statistic.NumberOfFiringRangeVisited - statistic.NumberOfHits
+}
+
+// GetPenaltyTime возвращает штрафное время участника за промахи.
+// Вне режима штрафного времени штраф всегда равен нулю.
+func GetPenaltyTime(statistic *entities.Statistic, config *config.Config) time.Duration {
+	if !IsPenaltyTimeMode(config) {
+		return 0
+	}
+	return time.Duration(GetNumberOfMisses(statistic)) * time.Duration(config.PenaltyTime)
+}
+
+// GetAdjustedTotalTime вычисляет общее время участника с учётом штрафного времени за промахи.
+func GetAdjustedTotalTime(statistic *entities.Statistic, config *config.Config) string {
+	if GetResultStatus(statistic) != entities.StatusFinished {
+		return GetTotalTime(statistic, config)
+	}
+
+	totalInterval := statistic.ActualFinish.Sub(statistic.RequiredStart) + statistic.TimeCorrection + GetPenaltyTime(statistic, config)
+
+	return formatDuration(totalInterval, config)
+}
+
+// GetRawAndPenaltyTime возвращает чистое время прохождения дистанции и начисленное штрафное время.
+func GetRawAndPenaltyTime(statistic *entities.Statistic, config *config.Config) string {
+	penaltyTimeStr := formatDuration(GetPenaltyTime(statistic, config), config)
+	if GetResultStatus(statistic) != entities.StatusFinished {
+		return fmt.Sprintf("{, +%s}", penaltyTimeStr)
+	}
+
+	rawTimeStr := formatDuration(statistic.ActualFinish.Sub(statistic.RequiredStart), config)
+
+	return fmt.Sprintf("{%s, +%s}", rawTimeStr, penaltyTimeStr)
+}
@@ -0,0 +1,171 @@
+package services
+
+import (
+	"bufio"
+	"cmp"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"system_prototype_for_biathlon_competitions/internal/config"
+	"system_prototype_for_biathlon_competitions/internal/messages"
+	"system_prototype_for_biathlon_competitions/internal/storage"
+	"time"
+)
+
+// Format of the jury report:
+// [time of leaving the firing range] competitorID firing range(N): violation details +automatic penalty
+//
+// Example:
+// [10:12:45.003] 3 firing range(1): skipped 2 penalty laps +00:04:00.000
+// [10:24:49.905] 4 firing range(2): left penalty laps after 00:00:20.000, minimum for 2 laps is 00:00:30.000, missing 1
+// [10:31:02.114] 5 firing range(2): entered 1 penalty laps at 10:31:20.000, never left them
+
+// Виды нарушений прохождения штрафных кругов.
+const (
+	PenaltyViolationSkipped = "Skipped" // Участник должен был пройти штрафные круги, но не заходил на них
+	PenaltyViolationShort   = "Short"   // Участник покинул штрафные круги подозрительно быстро
+	PenaltyViolationNotLeft = "NotLeft" // Участник зашёл на штрафные круги, но выход с них не зафиксирован
+)
+
+// PenaltyViolation представляет собой нарушение прохождения штрафных кругов, выявленное проверкой.
+type PenaltyViolation struct {
+	CompetitorID string        // Идентификатор участника
+	Visit        int           // Порядковый номер посещения огневого рубежа (с нуля)
+	FiringRange  string        // Номер огневого рубежа
+	LeftRange    time.Time     // Время ухода с огневого рубежа
+	Entry        time.Time     // Время входа на штрафные круги (нулевое, если участник не заходил)
+	Kind         string        // Вид нарушения
+	Owed         int           // Количество назначенных штрафных кругов
+	Missing      int           // Оценка количества непройденных штрафных кругов
+	Actual       time.Duration // Фактическое время на штрафных кругах
+	Minimum      time.Duration // Минимальное правдоподобное время на штрафных кругах
+	Penalty      time.Duration // Автоматически начисленный штраф
+}
+
+// minPenaltyLoopTime возвращает минимальное правдоподобное время одного штрафного круга
+// по PenaltyLen и PenaltyLoopMaxSpeed.
+func minPenaltyLoopTime(cfg *config.Config) time.Duration {
+	maxSpeed := cfg.PenaltyLoopMaxSpeed
+	if maxSpeed <= 0 {
+		maxSpeed = config.DefaultPenaltyLoopMaxSpeed
+	}
+
+	return time.Duration(float64(cfg.PenaltyLen) / maxSpeed * float64(time.Second))
+}
+
+// CheckPenaltyLaps сравнивает время, проведённое на штрафных кругах после каждого посещения
+// огневого рубежа, с минимальным правдоподобным временем для PenaltyLen × назначенных кругов
+// и возвращает найденные нарушения, упорядоченные по времени ухода с огневого рубежа.
+func CheckPenaltyLaps(statistics storage.StatisticRepository, config *config.Config) ([]*PenaltyViolation, error) {
+	if IsPenaltyTimeMode(config) || config.PenaltyLen <= 0 {
+		return nil, nil
+	}
+
+	minLoopTime := minPenaltyLoopTime(config)
+	loopPenalty := time.Duration(config.SkippedLoopPenalty)
+
+	var violations []*PenaltyViolation
+	for _, statistic := range statistics.ListStatistics() {
+		for i, visit := range statistic.PenaltyVisits {
+			if visit.Owed == 0 {
+				continue
+			}
+
+			violation := &PenaltyViolation{
+				CompetitorID: statistic.CompetitorID,
+				Visit:        i,
+				FiringRange:  visit.FiringRange,
+				LeftRange:    visit.LeftRange,
+				Entry:        visit.Entry,
+				Owed:         visit.Owed,
+				Minimum:      time.Duration(visit.Owed) * minLoopTime,
+			}
+			switch {
+			case visit.Entry.IsZero():
+				violation.Kind = PenaltyViolationSkipped
+				violation.Missing = visit.Owed
+			case visit.Exit.IsZero():
+				// Непройденные круги не оцениваются, и штраф не начисляется: решение принимает жюри.
+				violation.Kind = PenaltyViolationNotLeft
+			case visit.Exit.Sub(visit.Entry) < violation.Minimum:
+				violation.Kind = PenaltyViolationShort
+				violation.Actual = visit.Exit.Sub(visit.Entry)
+				violation.Missing = visit.Owed - int(violation.Actual/minLoopTime)
+			default:
+				continue
+			}
+			violation.Penalty = time.Duration(violation.Missing) * loopPenalty
+			violations = append(violations, violation)
+		}
+	}
+
+	slices.SortFunc(violations, func(a, b *PenaltyViolation) int {
+		if c := a.LeftRange.Compare(b.LeftRange); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.CompetitorID, b.CompetitorID)
+	})
+
+	return violations, nil
+}
+
+// ApplyPenaltyViolations добавляет автоматически начисленные штрафы к поправке итогового времени участников.
+func ApplyPenaltyViolations(statistics storage.StatisticRepository, violations []*PenaltyViolation) {
+	for _, violation := range violations {
+		if statistic := statistics.GetStatistic(violation.CompetitorID); statistic != nil {
+			statistic.TimeCorrection += violation.Penalty
+		}
+	}
+}
+
+// MakeJuryReport создает отчёт для жюри о нарушениях прохождения штрафных кругов на языке каталога catalog
+// и записывает его в файл 'report_jury'.
+func MakeJuryReport(violations []*PenaltyViolation, catalog *messages.Catalog) error {
+	reportFile, err := os.Create("report_jury")
+	if err != nil {
+		return fmt.Errorf("failed to create jury report file: %w", err)
+	}
+	defer reportFile.Close()
+
+	return WriteJuryReport(reportFile, violations, catalog)
+}
+
+// WriteJuryReport записывает в w отчёт для жюри о нарушениях прохождения штрафных кругов на языке каталога catalog.
+func WriteJuryReport(w io.Writer, violations []*PenaltyViolation, catalog *messages.Catalog) error {
+	writer := bufio.NewWriter(w)
+	for _, violation := range violations {
+		if _, err := writer.WriteString(FormatPenaltyViolation(violation, catalog) + "\n"); err != nil {
+			return fmt.Errorf("failed to write line in jury report: %w", err)
+		}
+	}
+
+	if err := writer.Flush(); err != nil {
+		return fmt.Errorf("failed to flush jury report: %w", err)
+	}
+
+	return nil
+}
+
+// FormatPenaltyViolation возвращает строковое представление нарушения для отчёта жюри на языке каталога catalog.
+func FormatPenaltyViolation(violation *PenaltyViolation, catalog *messages.Catalog) string {
+	leftRangeStr := violation.LeftRange.Format("15:04:05.000")
+	var details string
+	switch violation.Kind {
+	case PenaltyViolationSkipped:
+		details = catalog.Format(messages.JurySkipped, violation.Owed)
+	case PenaltyViolationShort:
+		actualStr := formatDuration(violation.Actual, nil)
+		minimumStr := formatDuration(violation.Minimum, nil)
+		details = catalog.Format(messages.JuryShort, actualStr, violation.Owed, minimumStr, violation.Missing)
+	case PenaltyViolationNotLeft:
+		details = catalog.Format(messages.JuryNotLeft, violation.Owed, violation.Entry.Format("15:04:05.000"))
+	}
+
+	line := catalog.Format(messages.JuryViolation, leftRangeStr, violation.CompetitorID, violation.FiringRange, details)
+	if violation.Penalty > 0 {
+		line += " +" + formatDuration(violation.Penalty, nil)
+	}
+
+	return line
+}
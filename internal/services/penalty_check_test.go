@@ -0,0 +1,103 @@
+package services_test
+
+import (
+	"system_prototype_for_biathlon_competitions/internal/config"
+	"system_prototype_for_biathlon_competitions/internal/entities"
+	"system_prototype_for_biathlon_competitions/internal/messages"
+	"system_prototype_for_biathlon_competitions/internal/services"
+	"system_prototype_for_biathlon_competitions/internal/storage"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestCheckPenaltyLaps тестирует функцию CheckPenaltyLaps.
+func TestCheckPenaltyLaps(t *testing.T) {
+	leftRange := time.Date(2023, 10, 1, 10, 10, 0, 0, time.UTC)
+	cfg := &config.Config{PenaltyLen: 150, PenaltyLoopMaxSpeed: 10}
+
+	t.Run("penalty laps completed", func(t *testing.T) {
+		statistics := storage.MemoryStatistics{
+			"1": {CompetitorID: "1", PenaltyVisits: []*entities.PenaltyVisit{
+				{FiringRange: "1", LeftRange: leftRange, Owed: 2, Entry: leftRange.Add(5 * time.Second), Exit: leftRange.Add(50 * time.Second)},
+				{FiringRange: "2", LeftRange: leftRange.Add(10 * time.Minute)},
+			}},
+		}
+
+		violations, err := services.CheckPenaltyLaps(statistics, cfg)
+		require.NoError(t, err)
+		require.Empty(t, violations)
+	})
+
+	t.Run("skipped penalty laps", func(t *testing.T) {
+		statistics := storage.MemoryStatistics{
+			"1": {CompetitorID: "1", PenaltyVisits: []*entities.PenaltyVisit{
+				{FiringRange: "1", LeftRange: leftRange, Owed: 2},
+			}},
+		}
+
+		violations, err := services.CheckPenaltyLaps(statistics, cfg)
+		require.NoError(t, err)
+		require.Len(t, violations, 1)
+		require.Equal(t, services.PenaltyViolationSkipped, violations[0].Kind)
+		require.Equal(t, 2, violations[0].Missing)
+		require.Zero(t, violations[0].Penalty)
+	})
+
+	t.Run("short penalty laps with automatic penalty", func(t *testing.T) {
+		statistics := storage.MemoryStatistics{
+			"1": {CompetitorID: "1", PenaltyVisits: []*entities.PenaltyVisit{
+				{FiringRange: "1", LeftRange: leftRange, Owed: 3, Entry: leftRange.Add(5 * time.Second), Exit: leftRange.Add(25 * time.Second)},
+			}},
+		}
+		penaltyCfg := *cfg
+		penaltyCfg.SkippedLoopPenalty = config.Duration(2 * time.Minute)
+
+		violations, err := services.CheckPenaltyLaps(statistics, &penaltyCfg)
+		require.NoError(t, err)
+		require.Len(t, violations, 1)
+		require.Equal(t, services.PenaltyViolationShort, violations[0].Kind)
+		require.Equal(t, 45*time.Second, violations[0].Minimum)
+		require.Equal(t, 2, violations[0].Missing)
+		require.Equal(t, 4*time.Minute, violations[0].Penalty)
+		require.Equal(t, "[10:10:00.000] 1 firing range(1): left penalty laps after 00:00:20.000, minimum for 3 laps is 00:00:45.000, missing 2 +00:04:00.000", services.FormatPenaltyViolation(violations[0], nil))
+
+		services.ApplyPenaltyViolations(statistics, violations)
+		require.Equal(t, 4*time.Minute, statistics.GetStatistic("1").TimeCorrection)
+	})
+
+	t.Run("penalty laps entered but not left", func(t *testing.T) {
+		statistics := storage.MemoryStatistics{
+			"1": {CompetitorID: "1", PenaltyVisits: []*entities.PenaltyVisit{
+				{FiringRange: "2", LeftRange: leftRange, Owed: 1, Entry: leftRange.Add(18 * time.Second)},
+			}},
+		}
+		penaltyCfg := *cfg
+		penaltyCfg.SkippedLoopPenalty = config.Duration(2 * time.Minute)
+
+		violations, err := services.CheckPenaltyLaps(statistics, &penaltyCfg)
+		require.NoError(t, err)
+		require.Len(t, violations, 1)
+		require.Equal(t, services.PenaltyViolationNotLeft, violations[0].Kind)
+		require.Zero(t, violations[0].Missing)
+		require.Zero(t, violations[0].Penalty)
+		require.Equal(t, "[10:10:00.000] 1 firing range(2): entered 1 penalty laps at 10:10:18.000, never left them", services.FormatPenaltyViolation(violations[0], nil))
+
+		catalog, err := messages.New("ru")
+		require.NoError(t, err)
+		require.Equal(t, "[10:10:00.000] 1 огневой рубеж(2): вошёл на штрафные круги (1) в 10:10:18.000 и не ушёл с них", services.FormatPenaltyViolation(violations[0], catalog))
+	})
+
+	t.Run("penalty time mode", func(t *testing.T) {
+		statistics := storage.MemoryStatistics{
+			"1": {CompetitorID: "1", PenaltyVisits: []*entities.PenaltyVisit{
+				{FiringRange: "1", LeftRange: leftRange, Owed: 2},
+			}},
+		}
+
+		violations, err := services.CheckPenaltyLaps(statistics, &config.Config{PenaltyLen: 150, PenaltyMode: config.PenaltyModeTime})
+		require.NoError(t, err)
+		require.Empty(t, violations)
+	})
+}
@@ -0,0 +1,169 @@
+package services
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"system_prototype_for_biathlon_competitions/internal/config"
+	"system_prototype_for_biathlon_competitions/internal/entities"
+	"system_prototype_for_biathlon_competitions/internal/messages"
+	"time"
+)
+
+// Format of the penalty loops report:
+// a section per competitor with a line per firing range visit and the total over all visits:
+// firing range(N): hits/shots, owed N, completed N [entry-exit] {time on the loops, average speed}
+//
+// Example:
+// == Penalty loops: 1 ==
+// firing range(1): 3/5, owed 2, completed 2 [10:09:03.232-10:10:43.232] {00:01:40.000, 3.000}
+// firing range(2): 5/5, no penalty loops
+// total: completed 2 of 2 {00:01:40.000, 3.000}
+
+// lastPenaltyVisit возвращает последнее посещение огневого рубежа участником или nil.
+func lastPenaltyVisit(statistic *entities.Statistic) *entities.PenaltyVisit {
+	if len(statistic.PenaltyVisits) == 0 {
+		return nil
+	}
+
+	return statistic.PenaltyVisits[len(statistic.PenaltyVisits)-1]
+}
+
+// openPenaltyVisit возвращает посещение огневого рубежа, с которого участник ещё не ушёл, или nil.
+func openPenaltyVisit(statistic *entities.Statistic) *entities.PenaltyVisit {
+	if visit := lastPenaltyVisit(statistic); visit != nil && visit.LeftRange.IsZero() {
+		return visit
+	}
+
+	return nil
+}
+
+// recordShot отмечает выстрел в момент shotTime при посещении огневого рубежа visit.
+func recordShot(visit *entities.PenaltyVisit, shotTime time.Time) {
+	if visit.FirstShot.IsZero() {
+		visit.FirstShot = shotTime
+	}
+	visit.LastShot = shotTime
+}
+
+// recordHit засчитывает попадание в мишень target при посещении огневого рубежа visit
+// и возвращает false, если мишень уже была поражена.
+func recordHit(statistic *entities.Statistic, visit *entities.PenaltyVisit, target string) bool {
+	if slices.Contains(visit.Hits, target) {
+		return false
+	}
+	visit.Hits = append(visit.Hits, target)
+	statistic.NumberOfHits++
+	statistic.NumberOfPenaltyLaps--
+
+	return true
+}
+
+// penaltyLoopTime возвращает время на штрафных кругах после посещения огневого рубежа visit и false,
+// если круги не были назначены или пройдены. Так время на штрафных кругах учитывается во всех отчётах.
+func penaltyLoopTime(visit *entities.PenaltyVisit) (time.Duration, bool) {
+	if visit.Owed == 0 || visit.Entry.IsZero() || visit.Exit.IsZero() {
+		return 0, false
+	}
+	duration := visit.Exit.Sub(visit.Entry)
+
+	return duration, duration > 0
+}
+
+// completePenaltyVisit засчитывает пройденными назначенные после посещения visit штрафные круги,
+// на которые хватило времени по минимальному времени круга (как в CheckPenaltyLaps), вычисляет
+// среднюю скорость на них и добавляет их количество и время к итогам участника.
+// Время на штрафных кругах без назначенных кругов в итоги не входит.
+func completePenaltyVisit(statistic *entities.Statistic, visit *entities.PenaltyVisit, config *config.Config) {
+	duration, ok := penaltyLoopTime(visit)
+	if !ok {
+		return
+	}
+
+	visit.Completed = visit.Owed
+	if minLoopTime := minPenaltyLoopTime(config); minLoopTime > 0 {
+		visit.Completed = min(visit.Owed, int(duration/minLoopTime))
+	}
+	if visit.Completed > 0 {
+		visit.Speed = float64(config.PenaltyLen*visit.Completed) / duration.Seconds()
+	}
+	statistic.NumberOfCompletionPenaltyLaps += visit.Completed
+	statistic.TotalTimeOfPenaltyLaps += duration
+}
+
+// GetPenaltyVisitLine возвращает строковое представление посещения огневого рубежа
+// и прохождения назначенных по его итогам штрафных кругов на языке каталога catalog.
+func GetPenaltyVisitLine(visit *entities.PenaltyVisit, config *config.Config, catalog *messages.Catalog) string {
+	line := catalog.Format(messages.PenaltyVisit, visit.FiringRange, len(visit.Hits), targetsPerVisit)
+	switch {
+	case visit.Owed == 0 && visit.Entry.IsZero():
+		return catalog.Format(messages.PenaltyNone, line)
+	case visit.Entry.IsZero():
+		return catalog.Format(messages.PenaltySkipped, line, visit.Owed)
+	case visit.Exit.IsZero():
+		return catalog.Format(messages.PenaltyNotLeft, line, visit.Owed, visit.Entry.Format("15:04:05.000"))
+	}
+
+	duration := visit.Exit.Sub(visit.Entry)
+	speed := ""
+	if visit.Speed > 0 {
+		speed = " " + formatSpeed(visit.Speed, config)
+	}
+
+	return catalog.Format(messages.PenaltyCompleted, line, visit.Owed, visit.Completed,
+		visit.Entry.Format("15:04:05.000"), visit.Exit.Format("15:04:05.000"), formatDuration(duration, config), speed)
+}
+
+// GetPenaltyTotalLine возвращает итог по штрафным кругам участника за все посещения огневых рубежей
+// на языке каталога catalog.
+func GetPenaltyTotalLine(statistic *entities.Statistic, config *config.Config, catalog *messages.Catalog) string {
+	owed := 0
+	for _, visit := range statistic.PenaltyVisits {
+		owed += visit.Owed
+	}
+
+	return catalog.Format(messages.PenaltyTotal, statistic.NumberOfCompletionPenaltyLaps, owed, GetTimeAndAvgSpeedForPenaltyLaps(statistic, config))
+}
+
+// MakePenaltyLoopsReport создает отчёт о штрафных кругах по каждому посещению огневых рубежей
+// и записывает его в файл 'report_penalty'.
+func (s *ReportService) MakePenaltyLoopsReport() error {
+	reportFile, err := os.Create("report_penalty")
+	if err != nil {
+		return fmt.Errorf("failed to create penalty loops report file: %w", err)
+	}
+	defer reportFile.Close()
+
+	return s.WritePenaltyLoopsReport(reportFile)
+}
+
+// WritePenaltyLoopsReport записывает в w раздел для каждого участника, посещавшего огневые рубежи,
+// в порядке итоговой таблицы. В режиме штрафного времени отчёт пуст.
+func (s *ReportService) WritePenaltyLoopsReport(w io.Writer) error {
+	writer := bufio.NewWriter(w)
+	if !IsPenaltyTimeMode(s.Config) {
+		for _, statistic := range s.SortStatistics() {
+			if len(statistic.PenaltyVisits) == 0 {
+				continue
+			}
+			lines := make([]string, 0, len(statistic.PenaltyVisits)+1)
+			for _, visit := range statistic.PenaltyVisits {
+				lines = append(lines, GetPenaltyVisitLine(visit, s.Config, s.Messages))
+			}
+			lines = append(lines, GetPenaltyTotalLine(statistic, s.Config, s.Messages))
+
+			title := s.Messages.Format(messages.ReportPenaltyLoops, GetCompetitorName(statistic.CompetitorID, s.Athletes))
+			if err := writeSection(writer, title, lines); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := writer.Flush(); err != nil {
+		return fmt.Errorf("failed to flush penalty loops report: %w", err)
+	}
+
+	return nil
+}
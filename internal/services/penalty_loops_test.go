@@ -0,0 +1,175 @@
+package services_test
+
+import (
+	"strings"
+	"system_prototype_for_biathlon_competitions/internal/config"
+	"system_prototype_for_biathlon_competitions/internal/entities"
+	"system_prototype_for_biathlon_competitions/internal/messages"
+	"system_prototype_for_biathlon_competitions/internal/services"
+	"system_prototype_for_biathlon_competitions/internal/storage"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestPenaltyVisits тестирует учёт штрафных кругов по каждому посещению огневого рубежа.
+func TestPenaltyVisits(t *testing.T) {
+	cfg := &config.Config{Laps: 2, LapLen: 3000, PenaltyLen: 150, StartDelta: config.Duration(90 * time.Second)}
+	parse := func(t *testing.T, events string) *entities.Statistic {
+		service := services.NewParseService(&entities.Files{EventsFile: openTempFile(t, "events", events)})
+		statistics, err := service.ParseEvents(cfg)
+		require.NoError(t, err)
+		return statistics.GetStatistic("1")
+	}
+	start := "[09:00:00.000] 1 1\n" +
+		"[09:01:00.000] 2 1 10:00:00.000\n" +
+		"[10:00:01.000] 4 1\n"
+
+	t.Run("multiple visits with duplicate hits and range entries", func(t *testing.T) {
+		statistic := parse(t, start+
+			"[10:05:00.000] 5 1 1\n"+
+			"[10:05:01.000] 6 1 1\n"+
+			"[10:05:02.000] 6 1 1\n"+
+			"[10:05:03.000] 6 1 2\n"+
+			"[10:05:04.000] 5 1 1\n"+
+			"[10:05:05.000] 6 1 3\n"+
+			"[10:05:10.000] 7 1\n"+
+			"[10:05:20.000] 8 1\n"+
+			"[10:06:00.000] 9 1\n"+
+			"[10:10:00.000] 10 1\n"+
+			"[10:15:00.000] 5 1 2\n"+
+			"[10:15:01.000] 6 1 1\n"+
+			"[10:15:02.000] 6 1 2\n"+
+			"[10:15:03.000] 6 1 3\n"+
+			"[10:15:04.000] 6 1 4\n"+
+			"[10:15:10.000] 7 1\n"+
+			"[10:15:20.000] 8 1\n"+
+			"[10:16:10.000] 9 1\n"+
+			"[10:20:00.000] 10 1\n")
+
+		require.Equal(t, 2, statistic.NumberOfFiringRangeVisited)
+		require.Equal(t, 7, statistic.NumberOfHits)
+		require.Equal(t, 3, services.GetNumberOfMisses(statistic))
+		require.Len(t, statistic.PenaltyVisits, 2)
+
+		first := statistic.PenaltyVisits[0]
+		require.Equal(t, []string{"1", "2", "3"}, first.Hits)
+		require.Equal(t, 2, first.Owed)
+		require.Equal(t, 2, first.Completed)
+		require.InDelta(t, 7.5, first.Speed, 1e-9)
+
+		second := statistic.PenaltyVisits[1]
+		require.Equal(t, 1, second.Owed)
+		require.Equal(t, 1, second.Completed)
+		require.InDelta(t, 3.0, second.Speed, 1e-9)
+
+		require.Equal(t, 3, statistic.NumberOfCompletionPenaltyLaps)
+		require.Equal(t, 90*time.Second, statistic.TotalTimeOfPenaltyLaps)
+		require.Equal(t, "{00:01:30.000, 5.000}", services.GetTimeAndAvgSpeedForPenaltyLaps(statistic, cfg))
+	})
+
+	t.Run("zero misses", func(t *testing.T) {
+		statistic := parse(t, start+
+			"[10:05:00.000] 5 1 1\n"+
+			"[10:05:01.000] 6 1 1\n"+
+			"[10:05:02.000] 6 1 2\n"+
+			"[10:05:03.000] 6 1 3\n"+
+			"[10:05:04.000] 6 1 4\n"+
+			"[10:05:05.000] 6 1 5\n"+
+			"[10:05:10.000] 7 1\n"+
+			"[10:05:20.000] 8 1\n"+
+			"[10:05:40.000] 9 1\n"+
+			"[10:10:00.000] 10 1\n")
+
+		visit := statistic.PenaltyVisits[0]
+		require.Zero(t, visit.Owed)
+		require.Zero(t, visit.Completed)
+		require.Zero(t, visit.Speed)
+		require.Zero(t, statistic.NumberOfCompletionPenaltyLaps)
+		require.Zero(t, statistic.TotalTimeOfPenaltyLaps)
+		require.Equal(t, "{,}", services.GetTimeAndAvgSpeedForPenaltyLaps(statistic, cfg))
+		require.Equal(t, "firing range(1): 5/5, owed 0, completed 0 [10:05:20.000-10:05:40.000] {00:00:20.000,}", services.GetPenaltyVisitLine(visit, cfg, nil))
+	})
+
+	t.Run("short penalty loops", func(t *testing.T) {
+		statistic := parse(t, start+
+			"[10:05:00.000] 5 1 1\n"+
+			"[10:05:01.000] 6 1 1\n"+
+			"[10:05:02.000] 6 1 2\n"+
+			"[10:05:10.000] 7 1\n"+
+			"[10:05:20.000] 8 1\n"+
+			"[10:05:45.000] 9 1\n")
+
+		visit := statistic.PenaltyVisits[0]
+		require.Equal(t, 3, visit.Owed)
+		require.Equal(t, 1, visit.Completed)
+		require.InDelta(t, 6.0, visit.Speed, 1e-9)
+		require.Equal(t, 1, statistic.NumberOfCompletionPenaltyLaps)
+		require.Equal(t, 25*time.Second, statistic.TotalTimeOfPenaltyLaps)
+
+		violations, err := services.CheckPenaltyLaps(storage.MemoryStatistics{"1": statistic}, cfg)
+		require.NoError(t, err)
+		require.Len(t, violations, 1)
+		require.Equal(t, visit.Owed-visit.Completed, violations[0].Missing)
+	})
+
+	t.Run("exit without entry", func(t *testing.T) {
+		statistic := parse(t, start+
+			"[10:05:00.000] 5 1 1\n"+
+			"[10:05:10.000] 7 1\n"+
+			"[10:06:00.000] 9 1\n")
+
+		require.Equal(t, 5, statistic.PenaltyVisits[0].Owed)
+		require.Zero(t, statistic.PenaltyVisits[0].Completed)
+		require.Zero(t, statistic.NumberOfCompletionPenaltyLaps)
+	})
+}
+
+// TestWritePenaltyLoopsReport тестирует отчёт о штрафных кругах по посещениям огневых рубежей.
+func TestWritePenaltyLoopsReport(t *testing.T) {
+	at := func(clock string) time.Time {
+		parsed, err := time.Parse("15:04:05.000", clock)
+		require.NoError(t, err)
+		return parsed
+	}
+	statistics := storage.MemoryStatistics{
+		"1": {
+			CompetitorID:                  "1",
+			NumberOfCompletionPenaltyLaps: 2,
+			TotalTimeOfPenaltyLaps:        time.Minute,
+			PenaltyVisits: []*entities.PenaltyVisit{
+				{FiringRange: "1", Hits: []string{"1", "2", "3"}, Owed: 2, Entry: at("10:05:00.000"), Exit: at("10:06:00.000"), Completed: 2, Speed: 5},
+				{FiringRange: "2", Hits: []string{"1", "2", "3", "4", "5"}},
+				{FiringRange: "1", Hits: []string{"1", "2", "3", "4"}, Owed: 1},
+			},
+		},
+		"2": {CompetitorID: "2"},
+	}
+	cfg := &config.Config{Laps: 1, PenaltyLen: 150}
+
+	var output strings.Builder
+	require.NoError(t, services.NewReportService(statistics, cfg, nil).WritePenaltyLoopsReport(&output))
+	require.Equal(t, "== Penalty loops: 1 ==\n"+
+		"firing range(1): 3/5, owed 2, completed 2 [10:05:00.000-10:06:00.000] {00:01:00.000, 5.000}\n"+
+		"firing range(2): 5/5, no penalty loops\n"+
+		"firing range(1): 4/5, owed 1, skipped\n"+
+		"total: completed 2 of 3 {00:01:00.000, 5.000}\n\n", output.String())
+
+	output.Reset()
+	catalog, err := messages.New(messages.LocaleRussian)
+	require.NoError(t, err)
+	reportService := services.NewReportService(statistics, cfg, nil)
+	reportService.Messages = catalog
+	require.NoError(t, reportService.WritePenaltyLoopsReport(&output))
+	require.Equal(t, "== Штрафные круги: 1 ==\n"+
+		"огневой рубеж(1): 3/5, назначено 2, пройдено 2 [10:05:00.000-10:06:00.000] {00:01:00.000, 5.000}\n"+
+		"огневой рубеж(2): 5/5, без штрафных кругов\n"+
+		"огневой рубеж(1): 4/5, назначено 1, пропущены\n"+
+		"итого: пройдено 2 из 3 {00:01:00.000, 5.000}\n\n", output.String())
+
+	output.Reset()
+	cfg.PenaltyMode = config.PenaltyModeTime
+	require.NoError(t, services.NewReportService(statistics, cfg, nil).WritePenaltyLoopsReport(&output))
+	require.Empty(t, output.String())
+}
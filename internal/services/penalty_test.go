@@ -0,0 +1,54 @@
+package services_test
+
+import (
+	"system_prototype_for_biathlon_competitions/internal/config"
+	"system_prototype_for_biathlon_competitions/internal/entities"
+	"system_prototype_for_biathlon_competitions/internal/services"
+	"system_prototype_for_biathlon_competitions/internal/storage"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestGetAdjustedTotalTime тестирует функции расчёта времени в режиме штрафного времени.
+func TestGetAdjustedTotalTime(t *testing.T) {
+	cfg := &config.Config{PenaltyMode: config.PenaltyModeTime, PenaltyTime: config.Duration(time.Minute)}
+	statistic := &entities.Statistic{
+		IsFinished:                 true,
+		RequiredStart:              time.Date(2023, 10, 1, 10, 0, 0, 0, time.UTC),
+		ActualFinish:               time.Date(2023, 10, 1, 10, 30, 0, 0, time.UTC),
+		NumberOfFiringRangeVisited: 2,
+		NumberOfHits:               7,
+	}
+
+	require.Equal(t, 3, services.GetNumberOfMisses(statistic))
+	require.Equal(t, 3*time.Minute, services.GetPenaltyTime(statistic, cfg))
+	require.Equal(t, "00:33:00.000", services.GetAdjustedTotalTime(statistic, cfg))
+	require.Equal(t, "{00:30:00.000, +00:03:00.000}", services.GetRawAndPenaltyTime(statistic, cfg))
+
+	t.Run("laps mode has no time penalty", func(t *testing.T) {
+		require.Zero(t, services.GetPenaltyTime(statistic, &config.Config{}))
+		require.Equal(t, "00:30:00.000", services.GetAdjustedTotalTime(statistic, &config.Config{}))
+	})
+
+	t.Run("not finished competitor", func(t *testing.T) {
+		require.Equal(t, "NotFinished", services.GetAdjustedTotalTime(&entities.Statistic{ActualStart: statistic.RequiredStart}, cfg))
+	})
+}
+
+// TestSortStatisticsWithPenaltyTime тестирует сортировку с учётом штрафного времени.
+func TestSortStatisticsWithPenaltyTime(t *testing.T) {
+	start := time.Date(2023, 10, 1, 10, 0, 0, 0, time.UTC)
+	service := &services.ReportService{
+		Statistics: storage.MemoryStatistics{
+			"1": {CompetitorID: "1", IsFinished: true, RequiredStart: start, ActualFinish: start.Add(30 * time.Minute), NumberOfFiringRangeVisited: 1, NumberOfHits: 5},
+			"2": {CompetitorID: "2", IsFinished: true, RequiredStart: start, ActualFinish: start.Add(29 * time.Minute), NumberOfFiringRangeVisited: 1, NumberOfHits: 3},
+		},
+		Config: &config.Config{PenaltyMode: config.PenaltyModeTime, PenaltyTime: config.Duration(time.Minute)},
+	}
+
+	sortedStatistics := service.SortStatistics()
+	require.Equal(t, "1", sortedStatistics[0].CompetitorID)
+	require.Equal(t, "2", sortedStatistics[1].CompetitorID)
+}
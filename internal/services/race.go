@@ -0,0 +1,107 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"system_prototype_for_biathlon_competitions/internal/entities"
+	"system_prototype_for_biathlon_competitions/internal/messages"
+	"system_prototype_for_biathlon_competitions/internal/storage"
+	"time"
+)
+
+// RaceService предоставляет сервис для сохранения проведённых гонок и работы с ними.
+type RaceService struct {
+	repository storage.Repository
+}
+
+func NewRaceService(repository storage.Repository) *RaceService {
+	return &RaceService{repository: repository}
+}
+
+// SaveRace сохраняет гонку вместе с конфигурацией, исходными событиями, реестром спортсменов,
+// исправлениями жюри, статистикой участников из хранилища statistics и итоговой таблицей результатов.
+// Статистика и список участников, время сохранения, статус, версия и итоговая таблица заполняются сервисом:
+// каждое сохранение создаёт новую неофициальную версию результатов, сохраняя поданные протесты.
+// Официальные результаты перезаписать нельзя.
+func (s *RaceService) SaveRace(race *entities.Race, statistics storage.StatisticRepository) error {
+	previous, err := s.repository.GetRace(race.ID)
+	switch {
+	case err == nil:
+		if previous.Status == entities.ResultsOfficial {
+			return fmt.Errorf("official results of race %s cannot be overwritten", race.ID)
+		}
+		race.Version = previous.Version + 1
+		race.Protests = previous.Protests
+	case errors.Is(err, storage.ErrRaceNotFound):
+		race.Version = 1
+	default:
+		return fmt.Errorf("failed to get race: %w", err)
+	}
+
+	race.Statistics = make(map[string]*entities.Statistic)
+	race.Competitors = []string{}
+	for _, statistic := range statistics.ListStatistics() {
+		race.Statistics[statistic.CompetitorID] = statistic
+		race.Competitors = append(race.Competitors, statistic.CompetitorID)
+	}
+
+	race.CreatedAt = time.Now()
+	race.Status = entities.ResultsUnofficial
+	race.LastFinish = GetLastFinish(statistics)
+	race.Results = NewRaceReportService(race).ResultingLines()
+	if err := s.repository.SaveRace(race); err != nil {
+		return fmt.Errorf("failed to save race: %w", err)
+	}
+
+	return nil
+}
+
+// ListRaces возвращает список всех сохранённых гонок.
+func (s *RaceService) ListRaces() ([]*entities.Race, error) {
+	races, err := s.repository.ListRaces()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list races: %w", err)
+	}
+
+	return races, nil
+}
+
+// GetRace возвращает сохранённую гонку по идентификатору.
+func (s *RaceService) GetRace(id string) (*entities.Race, error) {
+	race, err := s.repository.GetRace(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get race: %w", err)
+	}
+
+	return race, nil
+}
+
+// ExportRace заново формирует итоговую таблицу сохранённой гонки с заголовками из каталога catalog
+// и записывает её в w.
+func (s *RaceService) ExportRace(id string, w io.Writer, catalog *messages.Catalog) error {
+	race, err := s.repository.GetRace(id)
+	if err != nil {
+		return fmt.Errorf("failed to get race: %w", err)
+	}
+
+	reportService := NewRaceReportService(race)
+	reportService.Messages = catalog
+	if err := reportService.WriteResultingTable(w); err != nil {
+		return fmt.Errorf("failed to export race: %w", err)
+	}
+
+	return nil
+}
+
+// NewRaceReportService создаёт сервис отчётов для сохранённой гонки, включая исправления жюри,
+// протесты, статус и версию результатов.
+func NewRaceReportService(race *entities.Race) *ReportService {
+	reportService := NewReportService(storage.MemoryStatistics(race.Statistics), race.Config, race.Athletes)
+	reportService.Corrections = race.Corrections
+	reportService.Protests = race.Protests
+	reportService.Status = race.Status
+	reportService.Version = race.Version
+
+	return reportService
+}
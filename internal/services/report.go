@@ -4,144 +4,242 @@ import (
 	"bufio"
 	"cmp"
 	"fmt"
+	"io"
 	"os"
 	"slices"
 	"strings"
 	"system_prototype_for_biathlon_competitions/internal/config"
 	"system_prototype_for_biathlon_competitions/internal/entities"
+	"system_prototype_for_biathlon_competitions/internal/messages"
+	"system_prototype_for_biathlon_competitions/internal/storage"
 	"time"
 )
 
 // Format:
 // - Total time includes the difference between scheduled and actual start time or NotStarted/NotFinished marks
 // - Time taken to complete each lap
-// - Average speed for each lap [m/s]
+// - Average speed for each lap [m/s or km/h]
 // - Time taken to complete penalty laps
-// - Average speed over penalty laps [m/s]
+// - Average speed over penalty laps [m/s or km/h]
 // - Number of hits/number of shots
+// Times and speeds are written by the formatting policy from the config (see format.go).
 //
 // Example:
 // [NotFinished] 1 [{00:29:03.872, 2.093}, {,}] {00:01:44.296, 0.481} 4/5
 
 // ReportService предоставляет сервис для работы с отчетами.
 type ReportService struct {
-	Statistics map[string]*entities.Statistic
-	Config     *config.Config
+	Statistics  storage.StatisticRepository
+	Config      *config.Config
+	Athletes    map[string]*entities.Athlete
+	Corrections []*entities.Correction
+	Protests    []*entities.Protest
+	Status      string
+	Version     int
+	Messages    *messages.Catalog // Каталог заголовков отчётов (по умолчанию на английском языке)
 }
 
-func NewReportService(statistics map[string]*entities.Statistic, config *config.Config) *ReportService {
+func NewReportService(statistics storage.StatisticRepository, config *config.Config, athletes map[string]*entities.Athlete) *ReportService {
 	return &ReportService{
 		Statistics: statistics,
 		Config:     config,
+		Athletes:   athletes,
 	}
 }
 
 // MakeResultingTable создает итоговую таблицу результатов соревнований и записывает её в файл 'report'.
 func (s *ReportService) MakeResultingTable() error {
-	sortedStatistics := s.SortStatistics()
 	reportFile, err := os.Create("report")
 	if err != nil {
 		return fmt.Errorf("failed to create report file: %w", err)
 	}
 	defer reportFile.Close()
 
-	writer := bufio.NewWriter(reportFile)
-	defer writer.Flush()
+	return s.WriteResultingTable(reportFile)
+}
 
-	for _, statistic := range sortedStatistics {
-		totalTime := GetTotalTime(statistic)
-		timeAndAvgSpeedForLaps := GetTimeAndAvgSpeedForLaps(statistic, s.Config)
-		timeAndAvgSpeedForPenaltyLaps := GetTimeAndAvgSpeedForPenaltyLaps(statistic, s.Config)
-		hitStatistics := GetHitStatistics(statistic)
-		line := fmt.Sprintf("[%s] %s [%s] %s %s\n", totalTime, statistic.CompetitorID, timeAndAvgSpeedForLaps, timeAndAvgSpeedForPenaltyLaps, hitStatistics)
-		if _, err := writer.WriteString(line); err != nil {
+// WriteResultingTable записывает итоговую таблицу результатов соревнований в w.
+// Если известен статус результатов, таблица предваряется заголовком со статусом и версией.
+// Если жюри вносило исправления или были поданы протесты, после таблицы записываются
+// разделы аудита и протестов.
+func (s *ReportService) WriteResultingTable(w io.Writer) error {
+	writer := bufio.NewWriter(w)
+	if err := s.writeStatusHeader(writer); err != nil {
+		return err
+	}
+	for _, line := range s.ResultingLines() {
+		if _, err := writer.WriteString(line + "\n"); err != nil {
 			return fmt.Errorf("failed to write line in report file: %w", err)
 		}
 	}
 
+	if len(s.Corrections) > 0 {
+		if _, err := fmt.Fprintf(writer, "\n== %s ==\n", s.Messages.Format(messages.ReportAudit)); err != nil {
+			return fmt.Errorf("failed to write audit header in report file: %w", err)
+		}
+		for i, correction := range s.Corrections {
+			if _, err := writer.WriteString(FormatCorrection(i+1, correction, s.Messages) + "\n"); err != nil {
+				return fmt.Errorf("failed to write audit line in report file: %w", err)
+			}
+		}
+	}
+
+	if len(s.Protests) > 0 {
+		if _, err := fmt.Fprintf(writer, "\n== %s ==\n", s.Messages.Format(messages.ReportProtests)); err != nil {
+			return fmt.Errorf("failed to write protests header in report file: %w", err)
+		}
+		for i, protest := range s.Protests {
+			if _, err := writer.WriteString(FormatProtest(i+1, protest, s.Messages) + "\n"); err != nil {
+				return fmt.Errorf("failed to write protest line in report file: %w", err)
+			}
+		}
+	}
+
+	if err := writer.Flush(); err != nil {
+		return fmt.Errorf("failed to flush report: %w", err)
+	}
+
 	return nil
 }
 
-// SortStatistics сортирует статистику участников соревнований в три категории:
-// завершившие, незавершившие и дисквалифицированные. 
-func (s *ReportService) SortStatistics() []*entities.Statistic {
-	finishedList := make([]*entities.Statistic, 0, len(s.Statistics))
-	notFinishedList := make([]*entities.Statistic, 0, len(s.Statistics))
-	disqualifiedList := make([]*entities.Statistic, 0, len(s.Statistics))
-	for _, statistic := range s.Statistics {
-		if statistic.IsFinished {
-			finishedList = append(finishedList, statistic)
-		} else if statistic.IsDisqualified {
-			disqualifiedList = append(disqualifiedList, statistic)
-		} else {
-			notFinishedList = append(notFinishedList, statistic)
+// resultsStatusKeys задаёт ключи сообщений статусов результатов гонки.
+var resultsStatusKeys = map[string]string{
+	entities.ResultsUnofficial:  messages.ResultsUnofficial,
+	entities.ResultsProvisional: messages.ResultsProvisional,
+	entities.ResultsOfficial:    messages.ResultsOfficial,
+}
+
+// writeStatusHeader записывает заголовок со статусом и версией результатов, если статус известен.
+func (s *ReportService) writeStatusHeader(writer *bufio.Writer) error {
+	if s.Status == "" {
+		return nil
+	}
+	status := s.Status
+	if key, ok := resultsStatusKeys[status]; ok {
+		status = s.Messages.Format(key)
+	}
+	if _, err := fmt.Fprintf(writer, "== %s ==\n", s.Messages.Format(messages.ReportResults, status, s.Version)); err != nil {
+		return fmt.Errorf("failed to write status header in report file: %w", err)
+	}
+
+	return nil
+}
+
+// ResultingLines формирует строки итоговой таблицы результатов соревнований.
+func (s *ReportService) ResultingLines() []string {
+	return s.formatLines(s.SortStatistics())
+}
+
+// formatLines формирует строки таблицы результатов для уже отсортированной статистики.
+func (s *ReportService) formatLines(sortedStatistics []*entities.Statistic) []string {
+	lines := make([]string, 0, len(sortedStatistics))
+	for _, statistic := range sortedStatistics {
+		totalTime := GetTotalTime(statistic, s.Config)
+		timeAndAvgSpeedForLaps := GetTimeAndAvgSpeedForLaps(statistic, s.Config)
+		penalty := GetTimeAndAvgSpeedForPenaltyLaps(statistic, s.Config)
+		if IsPenaltyTimeMode(s.Config) {
+			totalTime = GetAdjustedTotalTime(statistic, s.Config)
+			penalty = GetRawAndPenaltyTime(statistic, s.Config)
+		}
+		if status := FormatResultStatus(statistic, s.Messages); status != "" {
+			totalTime = status
 		}
+		hitStatistics := GetHitStatistics(statistic)
+		competitorName := GetCompetitorName(statistic.CompetitorID, s.Athletes)
+		line := fmt.Sprintf("[%s] %s [%s] %s %s", totalTime, competitorName, timeAndAvgSpeedForLaps, penalty, hitStatistics)
+		lines = append(lines, line)
+	}
+
+	return lines
+}
+
+// SortStatistics сортирует статистику участников соревнований по итоговым статусам:
+// финишировавшие, обойдённые на круг, не финишировавшие, не стартовавшие и дисквалифицированные.
+func (s *ReportService) SortStatistics() []*entities.Statistic {
+	return sortStatistics(s.Statistics.ListStatistics(), s.Config)
+}
+
+// sortStatistics упорядочивает переданный список статистики по правилам SortStatistics.
+// Финишировавшие упорядочиваются по итоговому времени (в режиме штрафного времени — с учётом штрафа),
+// обойдённые на круг — по количеству пройденных кругов и времени завершения последнего из них,
+// остальные — по идентификатору участника.
+func sortStatistics(statistics []*entities.Statistic, config *config.Config) []*entities.Statistic {
+	lists := make(map[string][]*entities.Statistic, len(resultStatusOrder))
+	for _, statistic := range statistics {
+		status := GetResultStatus(statistic)
+		lists[status] = append(lists[status], statistic)
 	}
 
-	slices.SortFunc(finishedList, func(a, b *entities.Statistic) int {
-		if a.ActualFinish.Sub(a.RequiredStart) > b.ActualFinish.Sub(b.RequiredStart) {
+	slices.SortFunc(lists[entities.StatusFinished], func(a, b *entities.Statistic) int {
+		aTotal := a.ActualFinish.Sub(a.RequiredStart) + a.TimeCorrection + GetPenaltyTime(a, config)
+		bTotal := b.ActualFinish.Sub(b.RequiredStart) + b.TimeCorrection + GetPenaltyTime(b, config)
+		if aTotal > bTotal {
 			return 1
-		} else if a.ActualFinish.Sub(a.RequiredStart) < b.ActualFinish.Sub(b.RequiredStart) {
+		} else if aTotal < bTotal {
 			return -1
 		}
 		return 0
 	})
-	slices.SortFunc(notFinishedList, func(a, b *entities.Statistic) int {
-		return cmp.Compare(a.CompetitorID, b.CompetitorID)
-	})
-	slices.SortFunc(disqualifiedList, func(a, b *entities.Statistic) int {
+	slices.SortFunc(lists[entities.StatusLapped], func(a, b *entities.Statistic) int {
+		if c := cmp.Compare(b.NumberOfEndedLaps, a.NumberOfEndedLaps); c != 0 {
+			return c
+		}
+		if a.NumberOfEndedLaps > 0 {
+			if c := a.TimeOfLapsCompletion[a.NumberOfEndedLaps-1].Compare(b.TimeOfLapsCompletion[b.NumberOfEndedLaps-1]); c != 0 {
+				return c
+			}
+		}
 		return cmp.Compare(a.CompetitorID, b.CompetitorID)
 	})
+	for _, status := range resultStatusOrder[2:] {
+		slices.SortFunc(lists[status], func(a, b *entities.Statistic) int {
+			return cmp.Compare(a.CompetitorID, b.CompetitorID)
+		})
+	}
 
-	sortedStatistics := append(append(finishedList, notFinishedList...), disqualifiedList...)
+	sortedStatistics := make([]*entities.Statistic, 0, len(statistics))
+	for _, status := range resultStatusOrder {
+		sortedStatistics = append(sortedStatistics, lists[status]...)
+	}
 
 	return sortedStatistics
 }
 
 // GetTotalTime вычисляет общее время прхождения эстафеты на основе статистики участника.
-func GetTotalTime(statistic *entities.Statistic) string {
-	if statistic.IsDisqualified {
-		return "NotStarted"
-	}
-	if !statistic.IsFinished {
-		return "NotFinished"
+// Время включает поправки, внесённые автоматическими штрафами и решениями жюри.
+// Для нефинишных статусов возвращается их представление на английском языке (NotStarted, NotFinished и т.д.).
+func GetTotalTime(statistic *entities.Statistic, config *config.Config) string {
+	if status := FormatResultStatus(statistic, nil); status != "" {
+		return status
 	}
 
-	totalInterval := statistic.ActualFinish.Sub(statistic.RequiredStart)
-	totalTime := formatDurationToTime(totalInterval)
-	totalTimeStr := totalTime.Format("15:04:05.000")
+	totalInterval := statistic.ActualFinish.Sub(statistic.RequiredStart) + statistic.TimeCorrection
 
-	return totalTimeStr
+	return formatDuration(totalInterval, config)
 }
 
 // GetTimeAndAvgSpeedForLaps вычисляет время прохождения и среднюю скорость для каждого круга.
+// Скорость считается по длине круга из описания трассы (см. config.Config.LapLength).
 func GetTimeAndAvgSpeedForLaps(statistic *entities.Statistic, config *config.Config) string {
-	distance := float64(config.LapLen)
 	pairs := make([]string, config.Laps)
 	for i := 0; i < config.Laps; i++ {
-		var (
-			takenInterval time.Duration
-			takenTime     time.Time
-		)
+		var takenInterval time.Duration
 		if i < len(statistic.TimeOfLapsCompletion) {
 			if i == 0 {
 				takenInterval = statistic.TimeOfLapsCompletion[i].Sub(statistic.ActualStart)
-				takenTime = formatDurationToTime(takenInterval)
 			} else {
 				takenInterval = statistic.TimeOfLapsCompletion[i].Sub(statistic.TimeOfLapsCompletion[i-1])
-				takenTime = formatDurationToTime(takenInterval)
 			}
 		}
 
 		var pair string
 		sec := takenInterval.Seconds()
-		takenTimeStr := takenTime.Format("15:04:05.000")
 		if sec == 0 {
 			pairs[i] = "{,}"
 			continue
 		}
-		avgSpeed := distance / sec
-		pair = fmt.Sprintf("{%s, %.3f}", takenTimeStr, avgSpeed)
+		avgSpeed := float64(config.LapLength(i+1)) / sec
+		pair = fmt.Sprintf("{%s, %s}", formatDuration(takenInterval, config), formatSpeed(avgSpeed, config))
 		pairs[i] = pair
 	}
 	mergedPairs := strings.Join(pairs, ", ")
@@ -149,20 +247,19 @@ func GetTimeAndAvgSpeedForLaps(statistic *entities.Statistic, config *config.Con
 	return mergedPairs
 }
 
-// GetTimeAndAvgSpeedForPenaltyLaps вычисляет общее время и среднюю скорость для штрафных кругов.
+// GetTimeAndAvgSpeedForPenaltyLaps вычисляет общее время и среднюю скорость для штрафных кругов,
+// пройденных после всех посещений огневых рубежей (см. completePenaltyVisit).
 func GetTimeAndAvgSpeedForPenaltyLaps(statistic *entities.Statistic, config *config.Config) string {
 	var pair string
-	distance := float64(config.PenaltyLen*statistic.NumberOfCompletionPenaltyLaps) * 1000
+	distance := float64(config.PenaltyLen * statistic.NumberOfCompletionPenaltyLaps)
 	totalInterval := statistic.TotalTimeOfPenaltyLaps
-	msec := float64(totalInterval.Milliseconds())
+	sec := totalInterval.Seconds()
 
-	if msec == 0 {
+	if sec == 0 {
 		return "{,}"
 	}
-	avgSpeed := distance / msec
-	totalTime := formatDurationToTime(totalInterval)
-	totalTimeStr := totalTime.Format("15:04:05.000")
-	pair = fmt.Sprintf("{%s, %.3f}", totalTimeStr, avgSpeed)
+	avgSpeed := distance / sec
+	pair = fmt.Sprintf("{%s, %s}", formatDuration(totalInterval, config), formatSpeed(avgSpeed, config))
 
 	return pair
 }
@@ -171,7 +268,7 @@ func GetTimeAndAvgSpeedForPenaltyLaps(statistic *entities.Statistic, config *con
 // в формате "количество попаданий/общее количество выстрелов".
 func GetHitStatistics(statistic *entities.Statistic) string {
 	numberOfHits := statistic.NumberOfHits
-	numberOfShots := 5 * statistic.NumberOfFiringRangeVisited
+	numberOfShots := targetsPerVisit * statistic.NumberOfFiringRangeVisited
 	stat := fmt.Sprintf("%d/%d", numberOfHits, numberOfShots)
 
 	return stat
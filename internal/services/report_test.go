@@ -4,6 +4,7 @@ import (
 	"system_prototype_for_biathlon_competitions/internal/config"
 	"system_prototype_for_biathlon_competitions/internal/entities"
 	"system_prototype_for_biathlon_competitions/internal/services"
+	"system_prototype_for_biathlon_competitions/internal/storage"
 	"testing"
 	"time"
 
@@ -14,12 +15,12 @@ import (
 func TestSortStatistics(t *testing.T) {
 	tests := []struct {
 		name       string
-		statistics map[string]*entities.Statistic
+		statistics storage.MemoryStatistics
 		expected   []*entities.Statistic
 	}{
 		{
 			name: "All competitors finished",
-			statistics: map[string]*entities.Statistic{
+			statistics: storage.MemoryStatistics{
 				"1": {
 					CompetitorID:  "1",
 					IsFinished:    true,
@@ -50,7 +51,7 @@ func TestSortStatistics(t *testing.T) {
 		},
 		{
 			name: "Mixed competitors",
-			statistics: map[string]*entities.Statistic{
+			statistics: storage.MemoryStatistics{
 				"1": {
 					CompetitorID:  "1",
 					IsFinished:    true,
@@ -64,6 +65,7 @@ func TestSortStatistics(t *testing.T) {
 				"3": {
 					CompetitorID: "3",
 					IsFinished:   false,
+					ActualStart:  time.Date(2023, 10, 1, 10, 0, 1, 0, time.UTC),
 				},
 			},
 			expected: []*entities.Statistic{
@@ -83,9 +85,57 @@ func TestSortStatistics(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "All result statuses",
+			statistics: storage.MemoryStatistics{
+				"1": {
+					CompetitorID:         "1",
+					DisqualificationRule: "IBU 7.4.c",
+					IsFinished:           true,
+				},
+				"2": {
+					CompetitorID:   "2",
+					IsDisqualified: true,
+				},
+				"3": {
+					CompetitorID: "3",
+					ActualStart:  time.Date(2023, 10, 1, 10, 0, 1, 0, time.UTC),
+				},
+				"4": {
+					CompetitorID:         "4",
+					IsLapped:             true,
+					NumberOfEndedLaps:    1,
+					TimeOfLapsCompletion: []time.Time{time.Date(2023, 10, 1, 10, 12, 0, 0, time.UTC)},
+				},
+				"5": {
+					CompetitorID:         "5",
+					IsLapped:             true,
+					NumberOfEndedLaps:    2,
+					TimeOfLapsCompletion: []time.Time{time.Date(2023, 10, 1, 10, 12, 0, 0, time.UTC), time.Date(2023, 10, 1, 10, 24, 0, 0, time.UTC)},
+				},
+				"6": {
+					CompetitorID:  "6",
+					IsFinished:    true,
+					RequiredStart: time.Date(2023, 10, 1, 10, 0, 0, 0, time.UTC),
+					ActualFinish:  time.Date(2023, 10, 1, 10, 30, 0, 0, time.UTC),
+				},
+				"7": {
+					CompetitorID: "7",
+				},
+			},
+			expected: []*entities.Statistic{
+				{CompetitorID: "6"},
+				{CompetitorID: "5"},
+				{CompetitorID: "4"},
+				{CompetitorID: "3"},
+				{CompetitorID: "2"},
+				{CompetitorID: "7"},
+				{CompetitorID: "1"},
+			},
+		},
 		{
 			name: "All competitors disqualified",
-			statistics: map[string]*entities.Statistic{
+			statistics: storage.MemoryStatistics{
 				"2": {
 					CompetitorID:   "2",
 					IsDisqualified: true,
@@ -137,10 +187,33 @@ func TestGetTotalTime(t *testing.T) {
 		{
 			name: "Not finished competitor",
 			statistic: &entities.Statistic{
-				IsFinished: false,
+				IsFinished:  false,
+				ActualStart: time.Date(2023, 10, 1, 10, 0, 1, 0, time.UTC),
 			},
 			expected: "NotFinished",
 		},
+		{
+			name: "Registered competitor who never started",
+			statistic: &entities.Statistic{
+				RequiredStart: time.Date(2023, 10, 1, 10, 0, 0, 0, time.UTC),
+			},
+			expected: "NotStarted",
+		},
+		{
+			name: "Lapped competitor",
+			statistic: &entities.Statistic{
+				IsLapped: true,
+			},
+			expected: "Lapped",
+		},
+		{
+			name: "Competitor disqualified for a rule violation",
+			statistic: &entities.Statistic{
+				IsFinished:           true,
+				DisqualificationRule: "IBU 7.4.c",
+			},
+			expected: "Disqualified(IBU 7.4.c)",
+		},
 		{
 			name: "Finished competitor",
 			statistic: &entities.Statistic{
@@ -153,7 +226,7 @@ func TestGetTotalTime(t *testing.T) {
 	}
 
 	for _, tt := range tests {
-		result := services.GetTotalTime(tt.statistic)
+		result := services.GetTotalTime(tt.statistic, nil)
 		require.Equal(t, tt.expected, result)
 	}
 }
@@ -223,6 +296,23 @@ func TestGetTimeAndAvgSpeedForLaps(t *testing.T) {
 			},
 			expected: "{00:05:00.000, 3.333}, {00:07:00.000, 2.381}, {,}",
 		},
+		{
+			name: "Course with different lap lengths",
+			statistic: &entities.Statistic{
+				ActualStart: time.Date(2023, 10, 1, 10, 0, 0, 0, time.UTC),
+				TimeOfLapsCompletion: []time.Time{
+					time.Date(2023, 10, 1, 10, 5, 0, 0, time.UTC),
+					time.Date(2023, 10, 1, 10, 10, 0, 0, time.UTC),
+					time.Date(2023, 10, 1, 10, 15, 0, 0, time.UTC),
+				},
+			},
+			config: &config.Config{
+				LapLen: 1000,
+				Laps:   3,
+				Course: []config.CourseLap{{Length: 1500, FiringLine: 1}, {FiringLine: 2}, {Length: 3000}},
+			},
+			expected: "{00:05:00.000, 5.000}, {00:05:00.000, 3.333}, {00:05:00.000, 10.000}",
+		},
 	}
 
 	for _, tt := range tests {
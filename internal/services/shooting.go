@@ -0,0 +1,308 @@
+package services
+
+import (
+	"bufio"
+	"cmp"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"system_prototype_for_biathlon_competitions/internal/config"
+	"system_prototype_for_biathlon_competitions/internal/entities"
+	"system_prototype_for_biathlon_competitions/internal/messages"
+	"time"
+)
+
+// Format of the shooting report:
+// - a line per competitor who visited the firing range in the order of the final report:
+//   hits/shots and accuracy in total, hits per bout and accuracy per position
+// - the shooting rhythm of every firing range visit reported with event 14 (see shots.go);
+//   the section is omitted without event 14
+// - competitors who hit every target on every bout
+// - the fastest firing range visits (from arriving to leaving the range); equal times share a place
+// - targets by the number of misses
+// - a line per firing range (firing range number of event 5) with visits, accuracy and average range time
+// Without event 14 every bout counts as 5 shots.
+//
+// Example:
+// == Shooting ==
+// 2 #102 Anna Sokolova (RUS): 9/10 90.0%, bouts 5/5 4/5, prone 5/5 100.0%, standing 4/5 80.0%
+//
+// == Shooting rhythm ==
+// 2 #102 Anna Sokolova (RUS) firing range(1): +++++, intervals 00:00:03.100 00:00:02.900 00:00:03.000 00:00:03.000, 00:00:03.000 per shot
+//
+// == Clean shooting ==
+// 1 #101 Ivan Petrov (RUS): 10/10
+//
+// == Fastest range times ==
+// 1. [00:00:25.000] 1 #101 Ivan Petrov (RUS), firing range(1)
+//
+// == Missed targets ==
+// target 3: 2 misses
+//
+// == Firing ranges ==
+// firing range(1): 4 visits, 18/20 90.0%, average range time 00:00:31.000
+
+// fastestRangeTimes задаёт количество посещений огневых рубежей в разделе быстрейшего времени на рубеже.
+const fastestRangeTimes = 10
+
+// ShootingResult представляет собой результат стрельбы: количество попаданий и выстрелов.
+type ShootingResult struct {
+	Hits  int // Количество попаданий
+	Shots int // Количество выстрелов
+}
+
+// add добавляет к результату стрельбы попадания и выстрелы посещения огневого рубежа visit.
+func (r *ShootingResult) add(visit *entities.PenaltyVisit) {
+	r.Hits += len(visit.Hits)
+	r.Shots += GetShotsFired(visit)
+}
+
+// String возвращает строковое представление результата стрельбы: попадания/выстрелы и точность.
+func (r ShootingResult) String() string {
+	if r.Shots == 0 {
+		return "0/0"
+	}
+
+	return fmt.Sprintf("%d/%d %.1f%%", r.Hits, r.Shots, 100*float64(r.Hits)/float64(r.Shots))
+}
+
+// compareNumbers сравнивает номера мишеней или огневых рубежей как числа;
+// нечисловые номера следуют за числовыми в алфавитном порядке.
+func compareNumbers(a, b string) int {
+	x, errA := strconv.Atoi(a)
+	y, errB := strconv.Atoi(b)
+	switch {
+	case errA == nil && errB == nil:
+		return cmp.Compare(x, y)
+	case errA == nil:
+		return -1
+	case errB == nil:
+		return 1
+	default:
+		return strings.Compare(a, b)
+	}
+}
+
+// GetShotsFired возвращает количество выстрелов при посещении огневого рубежа visit:
+// по событиям 14 или, если они не передавались, по числу мишеней.
+func GetShotsFired(visit *entities.PenaltyVisit) int {
+	if len(visit.Shots) > 0 {
+		return len(visit.Shots)
+	}
+
+	return targetsPerVisit
+}
+
+// GetRangeTime возвращает время посещения огневого рубежа visit от выхода на рубеж до ухода с него
+// и false, если участник не ушёл с рубежа.
+func GetRangeTime(visit *entities.PenaltyVisit) (time.Duration, bool) {
+	if visit.Arrived.IsZero() || visit.LeftRange.IsZero() {
+		return 0, false
+	}
+
+	return visit.LeftRange.Sub(visit.Arrived), true
+}
+
+// GetVisitPositions возвращает положение для стрельбы на каждом посещении огневого рубежа участником.
+// Круг посещения определяется по числу кругов, завершённых до выхода на рубеж.
+func GetVisitPositions(statistic *entities.Statistic, config *config.Config) []string {
+	positions := make([]string, 0, len(statistic.PenaltyVisits))
+	for i, visit := range statistic.PenaltyVisits {
+		lap := 1
+		for _, completion := range statistic.TimeOfLapsCompletion {
+			if !completion.After(visit.Arrived) {
+				lap++
+			}
+		}
+		positions = append(positions, config.ShootingPosition(lap, i+1))
+	}
+
+	return positions
+}
+
+// positionNames сопоставляет положениям для стрельбы ключи их названий в отчёте о стрельбе.
+var positionNames = map[string]string{
+	config.PositionProne:    messages.ShootingProne,
+	config.PositionStanding: messages.ShootingStanding,
+}
+
+// GetShootingLine возвращает строку отчёта о стрельбе участника: итог, результаты стрельб и точность по положениям
+// на языке каталога catalog.
+func GetShootingLine(statistic *entities.Statistic, cfg *config.Config, catalog *messages.Catalog) string {
+	var total ShootingResult
+	byPosition := make(map[string]*ShootingResult)
+	bouts := make([]string, 0, len(statistic.PenaltyVisits))
+	positions := GetVisitPositions(statistic, cfg)
+	for i, visit := range statistic.PenaltyVisits {
+		total.add(visit)
+		if byPosition[positions[i]] == nil {
+			byPosition[positions[i]] = &ShootingResult{}
+		}
+		byPosition[positions[i]].add(visit)
+		bouts = append(bouts, fmt.Sprintf("%d/%d", len(visit.Hits), GetShotsFired(visit)))
+	}
+
+	line := catalog.Format(messages.ShootingLine, total, strings.Join(bouts, " "))
+	for _, position := range []string{config.PositionProne, config.PositionStanding} {
+		if result := byPosition[position]; result != nil {
+			line += fmt.Sprintf(", %s %s", catalog.Format(positionNames[position]), result)
+		}
+	}
+
+	return line
+}
+
+// IsCleanShooting сообщает, поразил ли участник все мишени на каждом посещении огневого рубежа.
+func IsCleanShooting(statistic *entities.Statistic) bool {
+	if len(statistic.PenaltyVisits) == 0 {
+		return false
+	}
+	for _, visit := range statistic.PenaltyVisits {
+		if len(visit.Hits) < targetsPerVisit || len(visit.Hits) < GetShotsFired(visit) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// MakeShootingReport создает отчёт о стрельбе и записывает его в файл 'report_shooting'.
+func (s *ReportService) MakeShootingReport() error {
+	reportFile, err := os.Create("report_shooting")
+	if err != nil {
+		return fmt.Errorf("failed to create shooting report file: %w", err)
+	}
+	defer reportFile.Close()
+
+	return s.WriteShootingReport(reportFile)
+}
+
+// WriteShootingReport записывает в w точность и ритм стрельбы участников, список стрелявших без промахов,
+// быстрейшее время на огневых рубежах, промахи по мишеням и итоги по огневым рубежам.
+func (s *ReportService) WriteShootingReport(w io.Writer) error {
+	type rangeVisit struct {
+		competitorID string
+		firingRange  string
+		duration     time.Duration
+	}
+	type rangeSummary struct {
+		visits    int
+		result    ShootingResult
+		rangeTime time.Duration
+		timed     int
+	}
+
+	var shooting, rhythm, clean []string
+	var rangeVisits []rangeVisit
+	misses := make(map[string]int)
+	summaries := make(map[string]*rangeSummary)
+	for _, statistic := range s.SortStatistics() {
+		if len(statistic.PenaltyVisits) == 0 {
+			continue
+		}
+		name := GetCompetitorName(statistic.CompetitorID, s.Athletes)
+		shooting = append(shooting, name+": "+GetShootingLine(statistic, s.Config, s.Messages))
+		if IsCleanShooting(statistic) {
+			var total ShootingResult
+			for _, visit := range statistic.PenaltyVisits {
+				total.add(visit)
+			}
+			clean = append(clean, fmt.Sprintf("%s: %d/%d", name, total.Hits, total.Shots))
+		}
+
+		for _, visit := range statistic.PenaltyVisits {
+			if len(visit.Shots) > 0 {
+				rhythm = append(rhythm, name+" "+GetShotRhythmLine(visit, s.Config, s.Messages))
+			}
+			for _, target := range GetMissedTargets(visit) {
+				misses[target]++
+			}
+			if summaries[visit.FiringRange] == nil {
+				summaries[visit.FiringRange] = &rangeSummary{}
+			}
+			summaries[visit.FiringRange].visits++
+			summaries[visit.FiringRange].result.add(visit)
+			if duration, ok := GetRangeTime(visit); ok {
+				rangeVisits = append(rangeVisits, rangeVisit{statistic.CompetitorID, visit.FiringRange, duration})
+				summaries[visit.FiringRange].rangeTime += duration
+				summaries[visit.FiringRange].timed++
+			}
+		}
+	}
+
+	slices.SortStableFunc(rangeVisits, func(a, b rangeVisit) int {
+		return cmp.Compare(a.duration, b.duration)
+	})
+	rangeVisits = rangeVisits[:min(len(rangeVisits), fastestRangeTimes)]
+	places := sharedPlaces(len(rangeVisits), func(i int) time.Duration { return rangeVisits[i].duration })
+	fastest := make([]string, 0, len(rangeVisits))
+	for i, visit := range rangeVisits {
+		fastest = append(fastest, s.Messages.Format(messages.ShootingFastest, places[i], formatDuration(visit.duration, s.Config),
+			GetCompetitorName(visit.competitorID, s.Athletes), visit.firingRange))
+	}
+
+	targets := make([]string, 0, len(misses))
+	for target := range misses {
+		targets = append(targets, target)
+	}
+	slices.SortFunc(targets, func(a, b string) int {
+		if c := cmp.Compare(misses[b], misses[a]); c != 0 {
+			return c
+		}
+		return compareNumbers(a, b)
+	})
+	missed := make([]string, 0, len(targets))
+	for _, target := range targets {
+		key := messages.ShootingMisses
+		if misses[target] == 1 {
+			key = messages.ShootingMiss
+		}
+		missed = append(missed, s.Messages.Format(key, target, misses[target]))
+	}
+
+	firingRanges := make([]string, 0, len(summaries))
+	for firingRange := range summaries {
+		firingRanges = append(firingRanges, firingRange)
+	}
+	slices.SortFunc(firingRanges, compareNumbers)
+	totals := make([]string, 0, len(firingRanges))
+	for _, firingRange := range firingRanges {
+		summary := summaries[firingRange]
+		line := s.Messages.Format(messages.ShootingRange, firingRange, summary.visits, summary.result)
+		if summary.timed > 0 {
+			line = s.Messages.Format(messages.ShootingRangeTime, line, formatDuration(summary.rangeTime/time.Duration(summary.timed), s.Config))
+		}
+		totals = append(totals, line)
+	}
+
+	writer := bufio.NewWriter(w)
+	sections := []struct {
+		title    string
+		lines    []string
+		optional bool // Раздел без строк не записывается
+	}{
+		{messages.ReportShooting, shooting, false},
+		{messages.ReportShootingRhythm, rhythm, true},
+		{messages.ReportCleanShooting, clean, false},
+		{messages.ReportFastestRange, fastest, false},
+		{messages.ReportMissedTargets, missed, false},
+		{messages.ReportFiringRanges, totals, false},
+	}
+	for _, section := range sections {
+		if section.optional && len(section.lines) == 0 {
+			continue
+		}
+		if err := writeSection(writer, s.Messages.Format(section.title), section.lines); err != nil {
+			return err
+		}
+	}
+
+	if err := writer.Flush(); err != nil {
+		return fmt.Errorf("failed to flush shooting report: %w", err)
+	}
+
+	return nil
+}
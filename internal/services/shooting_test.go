@@ -0,0 +1,115 @@
+package services_test
+
+import (
+	"strings"
+	"system_prototype_for_biathlon_competitions/internal/config"
+	"system_prototype_for_biathlon_competitions/internal/entities"
+	"system_prototype_for_biathlon_competitions/internal/messages"
+	"system_prototype_for_biathlon_competitions/internal/services"
+	"system_prototype_for_biathlon_competitions/internal/storage"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestShootingLine тестирует точность стрельбы участника по стрельбам и положениям.
+func TestShootingLine(t *testing.T) {
+	start := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	shots := func(results ...bool) []entities.Shot {
+		var shots []entities.Shot
+		for i, hit := range results {
+			shots = append(shots, entities.Shot{Time: start.Add(time.Duration(i) * time.Second), Target: "1", Hit: hit})
+		}
+		return shots
+	}
+	statistic := &entities.Statistic{
+		TimeOfLapsCompletion: []time.Time{start.Add(10 * time.Minute), start.Add(20 * time.Minute), start.Add(30 * time.Minute)},
+		PenaltyVisits: []*entities.PenaltyVisit{
+			{Arrived: start.Add(9 * time.Minute), Hits: []string{"1", "2", "3", "4", "5"}},
+			{Arrived: start.Add(19 * time.Minute), Hits: []string{"1", "2", "3"}, Shots: shots(true, false, true, true, false, false)},
+			{Arrived: start.Add(29 * time.Minute), Hits: []string{"1", "2", "3", "4"}},
+		},
+	}
+
+	cfg := &config.Config{Laps: 4}
+	require.Equal(t, []string{config.PositionProne, config.PositionStanding, config.PositionProne}, services.GetVisitPositions(statistic, cfg))
+	require.Equal(t, "12/16 75.0%, bouts 5/5 3/6 4/5, prone 9/10 90.0%, standing 3/6 50.0%", services.GetShootingLine(statistic, cfg, nil))
+	require.False(t, services.IsCleanShooting(statistic))
+
+	cfg.Course = []config.CourseLap{{FiringLine: 1}, {FiringLine: 1, Position: config.PositionProne}, {FiringLine: 1, Position: config.PositionStanding}, {}}
+	require.Equal(t, "12/16 75.0%, bouts 5/5 3/6 4/5, prone 8/11 72.7%, standing 4/5 80.0%", services.GetShootingLine(statistic, cfg, nil))
+
+	statistic.PenaltyVisits = statistic.PenaltyVisits[:1]
+	require.True(t, services.IsCleanShooting(statistic))
+	require.False(t, services.IsCleanShooting(&entities.Statistic{}))
+}
+
+// TestWriteShootingReport тестирует отчёт о стрельбе.
+func TestWriteShootingReport(t *testing.T) {
+	start := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	visit := func(firingRange string, arrived, rangeTime time.Duration, hits ...string) *entities.PenaltyVisit {
+		return &entities.PenaltyVisit{FiringRange: firingRange, Arrived: start.Add(arrived), LeftRange: start.Add(arrived + rangeTime), Hits: hits}
+	}
+	statistics := storage.MemoryStatistics{
+		"1": {
+			CompetitorID: "1", IsFinished: true, RequiredStart: start, ActualFinish: start.Add(20 * time.Minute),
+			PenaltyVisits: []*entities.PenaltyVisit{
+				visit("1", 5*time.Minute, 30*time.Second, "1", "2", "3"),
+				visit("2", 15*time.Minute, 25*time.Second, "1", "2", "3", "5"),
+			},
+		},
+		"2": {
+			CompetitorID: "2", IsFinished: true, RequiredStart: start, ActualFinish: start.Add(19 * time.Minute),
+			PenaltyVisits: []*entities.PenaltyVisit{
+				visit("1", 5*time.Minute, 25*time.Second, "1", "2", "3", "4", "5"),
+				{
+					FiringRange: "2", Arrived: start.Add(15 * time.Minute), Hits: []string{"1", "2", "3", "4", "5"},
+					FirstShot: start.Add(15*time.Minute + 10*time.Second), LastShot: start.Add(15*time.Minute + 22*time.Second),
+					Shots: []entities.Shot{
+						{Time: start.Add(15*time.Minute + 10*time.Second), Target: "1", Hit: true},
+						{Time: start.Add(15*time.Minute + 13*time.Second), Target: "2", Hit: true},
+						{Time: start.Add(15*time.Minute + 16*time.Second), Target: "3", Hit: true},
+						{Time: start.Add(15*time.Minute + 19*time.Second), Target: "4", Hit: true},
+						{Time: start.Add(15*time.Minute + 22*time.Second), Target: "5", Hit: true},
+					},
+				},
+			},
+		},
+		"3": {CompetitorID: "3"},
+	}
+
+	var output strings.Builder
+	reportService := services.NewReportService(statistics, &config.Config{Laps: 2}, nil)
+	require.NoError(t, reportService.WriteShootingReport(&output))
+	require.Equal(t, "== Shooting ==\n"+
+		"2: 10/10 100.0%, bouts 5/5 5/5, prone 5/5 100.0%, standing 5/5 100.0%\n"+
+		"1: 7/10 70.0%, bouts 3/5 4/5, prone 3/5 60.0%, standing 4/5 80.0%\n\n"+
+		"== Shooting rhythm ==\n"+
+		"2 firing range(2): +++++, intervals 00:00:03.000 00:00:03.000 00:00:03.000 00:00:03.000, 00:00:03.000 per shot\n\n"+
+		"== Clean shooting ==\n2: 10/10\n\n"+
+		"== Fastest range times ==\n"+
+		"1. [00:00:25.000] 2, firing range(1)\n"+
+		"1. [00:00:25.000] 1, firing range(2)\n"+
+		"3. [00:00:30.000] 1, firing range(1)\n\n"+
+		"== Missed targets ==\ntarget 4: 2 misses\ntarget 5: 1 miss\n\n"+
+		"== Firing ranges ==\n"+
+		"firing range(1): 2 visits, 8/10 80.0%, average range time 00:00:27.500\n"+
+		"firing range(2): 2 visits, 9/10 90.0%, average range time 00:00:25.000\n\n", output.String())
+
+	catalog, err := messages.New(messages.LocaleRussian)
+	require.NoError(t, err)
+	reportService.Messages = catalog
+	output.Reset()
+	require.NoError(t, reportService.WriteShootingReport(&output))
+	require.Contains(t, output.String(), "1: 7/10 70.0%, стрельбы 3/5 4/5, лёжа 3/5 60.0%, стоя 4/5 80.0%\n")
+	require.Contains(t, output.String(), "2 огневой рубеж(2): +++++, интервалы 00:00:03.000 00:00:03.000 00:00:03.000 00:00:03.000, 00:00:03.000 на выстрел\n")
+	require.Contains(t, output.String(), "1. [00:00:25.000] 2, огневой рубеж(1)\n")
+	require.Contains(t, output.String(), "мишень 4: промахов: 2\nмишень 5: промахов: 1\n")
+	require.Contains(t, output.String(), "огневой рубеж(1): посещений: 2, 8/10 80.0%, среднее время на рубеже 00:00:27.500\n")
+
+	delete(statistics, "2")
+	output.Reset()
+	require.NoError(t, services.NewReportService(statistics, &config.Config{Laps: 2}, nil).WriteShootingReport(&output))
+	require.NotContains(t, output.String(), "Shooting rhythm")
+}
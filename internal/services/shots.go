@@ -0,0 +1,127 @@
+package services
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"system_prototype_for_biathlon_competitions/internal/config"
+	"system_prototype_for_biathlon_competitions/internal/entities"
+	"system_prototype_for_biathlon_competitions/internal/messages"
+	"time"
+)
+
+// Format of the shooting rhythm of a firing range visit reported with event 14:
+// firing range(N): a symbol per shot in the order of shooting ('+' for a hit, '-' for a miss),
+// targets not hit, intervals between shots and the average time per shot
+//
+// Example:
+// [10:05:01.000] 14 1 1 hit
+// [10:05:04.500] 14 1 2 miss
+// [10:05:08.000] 14 1 3 hit
+// firing range(1): +-+, missed 2 4 5, intervals 00:00:03.500 00:00:03.500, 00:00:03.500 per shot
+
+// Результаты выстрела во входящем событии 14.
+const (
+	ShotHit  = "hit"  // Мишень поражена
+	ShotMiss = "miss" // Промах
+)
+
+// parseShotResult разбирает результат выстрела из события 14.
+func parseShotResult(result string) (bool, error) {
+	switch result {
+	case ShotHit:
+		return true, nil
+	case ShotMiss:
+		return false, nil
+	default:
+		return false, fmt.Errorf("unknown shot result: %q", result)
+	}
+}
+
+// GetShotPattern возвращает последовательность выстрелов посещения огневого рубежа visit.
+// Если выстрелы не передавались событием 14, возвращается пустая строка.
+func GetShotPattern(visit *entities.PenaltyVisit) string {
+	var pattern strings.Builder
+	for _, shot := range visit.Shots {
+		if shot.Hit {
+			pattern.WriteByte('+')
+		} else {
+			pattern.WriteByte('-')
+		}
+	}
+
+	return pattern.String()
+}
+
+// GetMissedTargets возвращает номера непоражённых мишеней посещения огневого рубежа visit по возрастанию:
+// мишеней с 1 по targetsPerVisit и мишеней, по которым стреляли в событиях 14, без попаданий событиями 6
+// и 14. Для лент только с событием 6 промахи определяются по непоражённым мишеням.
+func GetMissedTargets(visit *entities.PenaltyVisit) []string {
+	targets := make([]string, 0, targetsPerVisit)
+	for target := 1; target <= targetsPerVisit; target++ {
+		targets = append(targets, strconv.Itoa(target))
+	}
+	hits := slices.Clone(visit.Hits)
+	for _, shot := range visit.Shots {
+		if !slices.Contains(targets, shot.Target) {
+			targets = append(targets, shot.Target)
+		}
+		if shot.Hit {
+			hits = append(hits, shot.Target)
+		}
+	}
+
+	var missed []string
+	for _, target := range targets {
+		if !slices.Contains(hits, target) {
+			missed = append(missed, target)
+		}
+	}
+	slices.SortFunc(missed, compareNumbers)
+
+	return missed
+}
+
+// GetShotIntervals возвращает интервалы между соседними выстрелами посещения огневого рубежа visit (ритм стрельбы).
+func GetShotIntervals(visit *entities.PenaltyVisit) []time.Duration {
+	if len(visit.Shots) < 2 {
+		return nil
+	}
+
+	intervals := make([]time.Duration, 0, len(visit.Shots)-1)
+	for i := 1; i < len(visit.Shots); i++ {
+		intervals = append(intervals, visit.Shots[i].Time.Sub(visit.Shots[i-1].Time))
+	}
+
+	return intervals
+}
+
+// GetShotRhythmLine возвращает строку отчёта о стрельбе с последовательностью выстрелов посещения
+// огневого рубежа visit, непоражёнными мишенями, интервалами между выстрелами и средним временем на выстрел
+// на языке каталога catalog.
+func GetShotRhythmLine(visit *entities.PenaltyVisit, cfg *config.Config, catalog *messages.Catalog) string {
+	line := catalog.Format(messages.ShootingRhythm, visit.FiringRange, GetShotPattern(visit))
+	if missed := GetMissedTargets(visit); len(missed) > 0 {
+		line = catalog.Format(messages.ShootingRhythmMissed, line, strings.Join(missed, " "))
+	}
+	if intervals := GetShotIntervals(visit); len(intervals) > 0 {
+		formatted := make([]string, 0, len(intervals))
+		for _, interval := range intervals {
+			formatted = append(formatted, formatDuration(interval, cfg))
+		}
+		line = catalog.Format(messages.ShootingRhythmIntervals, line, strings.Join(formatted, " "), formatDuration(GetTimePerShot(visit), cfg))
+	}
+
+	return line
+}
+
+// GetTimePerShot возвращает среднее время на выстрел посещения огневого рубежа visit
+// от первого до последнего выстрела или 0, если выстрелов меньше двух.
+func GetTimePerShot(visit *entities.PenaltyVisit) time.Duration {
+	if len(visit.Shots) < 2 {
+		return 0
+	}
+
+	return visit.LastShot.Sub(visit.FirstShot) / time.Duration(len(visit.Shots)-1)
+}
@@ -0,0 +1,99 @@
+package services_test
+
+import (
+	"system_prototype_for_biathlon_competitions/internal/config"
+	"system_prototype_for_biathlon_competitions/internal/entities"
+	"system_prototype_for_biathlon_competitions/internal/services"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestShots тестирует учёт выстрелов по событию 14 и совместимость с лентами только с событием 6.
+func TestShots(t *testing.T) {
+	cfg := &config.Config{Laps: 1, LapLen: 3000, PenaltyLen: 150, StartDelta: config.Duration(90 * time.Second)}
+	parse := func(t *testing.T, events string) (*entities.Statistic, error) {
+		service := services.NewParseService(&entities.Files{EventsFile: openTempFile(t, "events", events)})
+		statistics, err := service.ParseEvents(cfg)
+		if err != nil {
+			return nil, err
+		}
+		return statistics.GetStatistic("1"), nil
+	}
+	start := "[09:00:00.000] 1 1\n" +
+		"[09:01:00.000] 2 1 10:00:00.000\n" +
+		"[10:00:01.000] 4 1\n" +
+		"[10:05:00.000] 5 1 1\n"
+
+	t.Run("shots with misses", func(t *testing.T) {
+		statistic, err := parse(t, start+
+			"[10:05:10.000] 14 1 1 miss\n"+
+			"[10:05:13.000] 14 1 2 hit\n"+
+			"[10:05:15.000] 14 1 3 hit\n"+
+			"[10:05:19.000] 14 1 4 miss\n"+
+			"[10:05:22.000] 14 1 5 hit\n"+
+			"[10:05:30.000] 7 1\n")
+		require.NoError(t, err)
+
+		visit := statistic.PenaltyVisits[0]
+		require.Len(t, visit.Shots, 5)
+		require.Equal(t, "1", visit.Shots[0].Target)
+		require.False(t, visit.Shots[0].Hit)
+		require.Equal(t, []string{"2", "3", "5"}, visit.Hits)
+		require.Equal(t, 2, visit.Owed)
+		require.Equal(t, 3, statistic.NumberOfHits)
+		require.Equal(t, 12*time.Second, visit.LastShot.Sub(visit.FirstShot))
+
+		require.Equal(t, "-++-+", services.GetShotPattern(visit))
+		require.Equal(t, []string{"1", "4"}, services.GetMissedTargets(visit))
+		require.Equal(t, []time.Duration{3 * time.Second, 2 * time.Second, 4 * time.Second, 3 * time.Second}, services.GetShotIntervals(visit))
+		require.Equal(t, 3*time.Second, services.GetTimePerShot(visit))
+		require.Equal(t, "firing range(1): -++-+, missed 1 4, intervals 00:00:03.000 00:00:02.000 00:00:04.000 00:00:03.000, 00:00:03.000 per shot",
+			services.GetShotRhythmLine(visit, cfg, nil))
+	})
+
+	t.Run("hits reported by both events", func(t *testing.T) {
+		statistic, err := parse(t, start+
+			"[10:05:10.000] 14 1 1 hit\n"+
+			"[10:05:10.000] 6 1 1\n"+
+			"[10:05:12.000] 14 1 2 miss\n"+
+			"[10:05:30.000] 7 1\n")
+		require.NoError(t, err)
+
+		visit := statistic.PenaltyVisits[0]
+		require.Equal(t, []string{"1"}, visit.Hits)
+		require.Equal(t, 1, statistic.NumberOfHits)
+		require.Equal(t, 4, visit.Owed)
+		require.Equal(t, "+-", services.GetShotPattern(visit))
+		require.Equal(t, []string{"2", "3", "4", "5"}, services.GetMissedTargets(visit))
+	})
+
+	t.Run("hits reported only by event 14", func(t *testing.T) {
+		visit := &entities.PenaltyVisit{FiringRange: "1", Shots: []entities.Shot{
+			{Target: "3", Hit: true},
+			{Target: "6", Hit: false},
+		}}
+		require.Equal(t, []string{"1", "2", "4", "5", "6"}, services.GetMissedTargets(visit))
+	})
+
+	t.Run("only event 6", func(t *testing.T) {
+		statistic, err := parse(t, start+
+			"[10:05:10.000] 6 1 2\n"+
+			"[10:05:12.000] 6 1 5\n"+
+			"[10:05:30.000] 7 1\n")
+		require.NoError(t, err)
+
+		visit := statistic.PenaltyVisits[0]
+		require.Empty(t, services.GetShotPattern(visit))
+		require.Equal(t, []string{"1", "3", "4"}, services.GetMissedTargets(visit))
+		require.Nil(t, services.GetShotIntervals(visit))
+		require.Zero(t, services.GetTimePerShot(visit))
+		require.Equal(t, 2*time.Second, visit.LastShot.Sub(visit.FirstShot))
+	})
+
+	t.Run("invalid shot result", func(t *testing.T) {
+		_, err := parse(t, start+"[10:05:10.000] 14 1 1 missed\n")
+		require.ErrorContains(t, err, `expected shot result hit or miss, found "missed"`)
+	})
+}
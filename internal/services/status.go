@@ -0,0 +1,60 @@
+package services
+
+import (
+	"system_prototype_for_biathlon_competitions/internal/entities"
+	"system_prototype_for_biathlon_competitions/internal/messages"
+)
+
+// Result statuses and their representation in reports:
+// FIN - total time
+// LAP - Lapped
+// DNF - NotFinished
+// DNS - NotStarted
+// DSQ - Disqualified(rule)
+//
+// Competitors are ranked in the order FIN, LAP, DNF, DNS, DSQ.
+
+// resultStatusOrder задаёт порядок следования статусов в итоговой таблице.
+var resultStatusOrder = []string{
+	entities.StatusFinished,
+	entities.StatusLapped,
+	entities.StatusDNF,
+	entities.StatusDNS,
+	entities.StatusDSQ,
+}
+
+// GetResultStatus возвращает итоговый статус участника. Участник, не стартовавший в свой стартовый интервал
+// или не стартовавший вовсе (без события 4), получает статус DNS, если он не дисквалифицирован за нарушение правил.
+func GetResultStatus(statistic *entities.Statistic) string {
+	switch {
+	case statistic.DisqualificationRule != "":
+		return entities.StatusDSQ
+	case statistic.IsDisqualified:
+		return entities.StatusDNS
+	case statistic.IsFinished:
+		return entities.StatusFinished
+	case statistic.IsLapped:
+		return entities.StatusLapped
+	case statistic.ActualStart.IsZero():
+		return entities.StatusDNS
+	default:
+		return entities.StatusDNF
+	}
+}
+
+// FormatResultStatus возвращает представление нефинишного статуса участника в отчётах
+// на языке каталога catalog. Для финишировавших участников возвращается пустая строка.
+func FormatResultStatus(statistic *entities.Statistic, catalog *messages.Catalog) string {
+	switch GetResultStatus(statistic) {
+	case entities.StatusLapped:
+		return catalog.Format(messages.StatusLapped)
+	case entities.StatusDNF:
+		return catalog.Format(messages.StatusNotFinished)
+	case entities.StatusDNS:
+		return catalog.Format(messages.StatusNotStarted)
+	case entities.StatusDSQ:
+		return catalog.Format(messages.StatusDisqualified, statistic.DisqualificationRule)
+	default:
+		return ""
+	}
+}
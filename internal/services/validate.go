@@ -0,0 +1,594 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"system_prototype_for_biathlon_competitions/internal/clock"
+	"system_prototype_for_biathlon_competitions/internal/config"
+	"system_prototype_for_biathlon_competitions/internal/messages"
+	"time"
+)
+
+// Validation of input files without processing the race. Issues are grouped by category:
+// Config      - missing, unparseable or implausible config values
+// Syntax      - malformed event lines and unknown event IDs
+// Event order - events earlier than the previous event (by the EventOrder mode of the config)
+// Competitors - events of unregistered competitors and repeated registrations
+// Sequence    - events illegal in the competitor's current state (e.g. a hit outside the firing range)
+//
+// Format:
+// == Category ==
+// <error|warning>: <location>: <message>
+// ...
+// N errors, M warnings
+//
+// Example:
+// == Sequence ==
+// error: line 7: competitor 3 hit a target outside the firing range in "[09:49:33.123] 6 3 1"
+
+// Серьёзность замечаний проверки.
+const (
+	SeverityError   = "error"   // Ошибка: файлы не могут быть обработаны или результаты будут неверны
+	SeverityWarning = "warning" // Предупреждение: значение допустимо, но подозрительно
+)
+
+// Категории замечаний проверки в порядке вывода.
+const (
+	CategoryConfig      = "Config"
+	CategorySyntax      = "Syntax"
+	CategoryOrder       = "Event order"
+	CategoryCompetitors = "Competitors"
+	CategorySequence    = "Sequence"
+)
+
+// validationCategories задаёт порядок вывода категорий замечаний.
+var validationCategories = []string{CategoryConfig, CategorySyntax, CategoryOrder, CategoryCompetitors, CategorySequence}
+
+// validationKeys задаёт ключи сообщений категорий и серьёзности замечаний.
+var validationKeys = map[string]string{
+	CategoryConfig:      messages.ValidateConfig,
+	CategorySyntax:      messages.ValidateSyntax,
+	CategoryOrder:       messages.ValidateOrder,
+	CategoryCompetitors: messages.ValidateCompetitors,
+	CategorySequence:    messages.ValidateSequence,
+	SeverityError:       messages.ValidateError,
+	SeverityWarning:     messages.ValidateWarning,
+}
+
+// Границы правдоподобных значений конфигурации, за которыми выводится предупреждение.
+const (
+	minPlausibleLapLen     = 500
+	maxPlausibleLapLen     = 10000
+	minPlausibleDistance   = 2000
+	maxPlausibleDistance   = 30000
+	minPlausiblePenaltyLen = 50
+	maxPlausiblePenaltyLen = 500
+	maxPlausibleStartDelta = 10 * time.Minute
+	maxPlausibleLoopSpeed  = 15.0
+)
+
+// ValidationIssue представляет собой замечание, найденное при проверке входных файлов.
+type ValidationIssue struct {
+	Severity string // Серьёзность замечания
+	Category string // Категория замечания
+	Message  string // Место и описание замечания
+}
+
+// ValidationReport представляет собой результат проверки входных файлов.
+type ValidationReport struct {
+	Issues []*ValidationIssue
+
+	catalog *messages.Catalog // Каталог сообщений замечаний и строк результата
+}
+
+// add добавляет замечание с описанием message в результат проверки.
+func (r *ValidationReport) add(severity, category, message string) {
+	r.Issues = append(r.Issues, &ValidationIssue{Severity: severity, Category: category, Message: message})
+}
+
+// addMessage добавляет замечание с описанием из каталога сообщений по ключу key.
+func (r *ValidationReport) addMessage(severity, category, key string, args ...any) {
+	r.add(severity, category, r.catalog.Format(key, args...))
+}
+
+// Count возвращает количество замечаний серьёзности severity.
+func (r *ValidationReport) Count(severity string) int {
+	count := 0
+	for _, issue := range r.Issues {
+		if issue.Severity == severity {
+			count++
+		}
+	}
+
+	return count
+}
+
+// HasErrors сообщает, найдены ли при проверке ошибки.
+func (r *ValidationReport) HasErrors() bool {
+	return r.Count(SeverityError) > 0
+}
+
+// Lines формирует строки результата проверки, сгруппированные по категориям, и итоговую строку.
+func (r *ValidationReport) Lines() []string {
+	var lines []string
+	for _, category := range validationCategories {
+		header := false
+		for _, issue := range r.Issues {
+			if issue.Category != category {
+				continue
+			}
+			if !header {
+				lines = append(lines, fmt.Sprintf("== %s ==", r.catalog.Format(validationKeys[category])))
+				header = true
+			}
+			lines = append(lines, fmt.Sprintf("%s: %s", r.catalog.Format(validationKeys[issue.Severity]), issue.Message))
+		}
+	}
+
+	return append(lines, r.catalog.Format(messages.ValidateSummary, r.Count(SeverityError), r.Count(SeverityWarning)))
+}
+
+// Validate проверяет конфигурационный файл и файл событий (или несколько источников событий),
+// не обрабатывая гонку, и возвращает найденные замечания. Ошибка возвращается, только если
+// файлы не удалось прочитать.
+func (s *ParseService) Validate() (*ValidationReport, error) {
+	report := &ValidationReport{catalog: s.catalog}
+
+	cfg, err := config.LoadFile(s.files.ConfigFile.Name(), s.files.ConfigFile)
+	loaded := true
+	var fieldErrors config.FieldErrors
+	if errors.As(err, &fieldErrors) {
+		for _, fieldErr := range fieldErrors {
+			report.add(SeverityError, CategoryConfig, fieldErr.Error())
+		}
+	} else if err != nil {
+		report.add(SeverityError, CategoryConfig, err.Error())
+		cfg = &config.Config{}
+		loaded = false
+	}
+	validateConfig(report, cfg)
+
+	// События проверяются и при ошибках в конфигурации: недопустимый режим порядка событий
+	// заменяется режимом по умолчанию.
+	eventsConfig := *cfg
+	if err := eventsConfig.Validate("eventOrder", "reorderWindow"); err != nil {
+		eventsConfig.EventOrder = ""
+	}
+
+	var lines []eventLine
+	var origins []string
+	if len(s.files.EventSources) > 0 {
+		merged, duplicates, err := mergeEventSources(s.files.EventSources, &eventsConfig, func(err error) error {
+			var lineErr *LineError
+			if !errors.As(err, &lineErr) {
+				return err
+			}
+			report.add(SeverityError, CategorySyntax, lineErr.describe())
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		for i, event := range merged {
+			lines = append(lines, eventLine{number: i + 1, text: event.text})
+			origins = append(origins, event.origin)
+		}
+		for _, duplicate := range duplicates {
+			report.add(SeverityWarning, CategoryOrder, FormatDuplicateEvent(duplicate, report.catalog))
+		}
+	} else {
+		events, err := readEventLines(s.files.EventsFile)
+		if err != nil {
+			return nil, err
+		}
+		for i, event := range events {
+			lines = append(lines, eventLine{number: i + 1, text: event})
+		}
+	}
+
+	handlers := s.handlers
+	if handlers == nil {
+		handlers = DefaultEventHandlers()
+	}
+	if err := validateEvents(report, &eventsConfig, handlers, lines, origins, loaded); err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+// validateConfig добавляет предупреждения о допустимых, но неправдоподобных значениях конфигурации.
+func validateConfig(report *ValidationReport, cfg *config.Config) {
+	warnf := func(key string, args ...any) {
+		report.addMessage(SeverityWarning, CategoryConfig, key, args...)
+	}
+
+	plausibleLaps := true
+	if cfg.LapLen > 0 && (cfg.LapLen < minPlausibleLapLen || cfg.LapLen > maxPlausibleLapLen) {
+		warnf(messages.ValidateLapLen, cfg.LapLen, minPlausibleLapLen, maxPlausibleLapLen)
+		plausibleLaps = false
+	}
+	for i, lap := range cfg.Course {
+		if lap.Length > 0 && (lap.Length < minPlausibleLapLen || lap.Length > maxPlausibleLapLen) {
+			warnf(messages.ValidateCourseLapLen, i+1, lap.Length, minPlausibleLapLen, maxPlausibleLapLen)
+			plausibleLaps = false
+		}
+	}
+	if distance := cfg.Distance(); plausibleLaps && distance > 0 && (distance < minPlausibleDistance || distance > maxPlausibleDistance) {
+		warnf(messages.ValidateDistance, distance, cfg.Laps, minPlausibleDistance, maxPlausibleDistance)
+	}
+	if !IsPenaltyTimeMode(cfg) && cfg.PenaltyLen > 0 && (cfg.PenaltyLen < minPlausiblePenaltyLen || cfg.PenaltyLen > maxPlausiblePenaltyLen) {
+		warnf(messages.ValidatePenaltyLen, cfg.PenaltyLen, minPlausiblePenaltyLen, maxPlausiblePenaltyLen)
+	}
+	if time.Duration(cfg.StartDelta) > maxPlausibleStartDelta {
+		warnf(messages.ValidateStartDelta, cfg.StartDelta, config.Duration(maxPlausibleStartDelta))
+	}
+	if cfg.PenaltyLoopMaxSpeed > maxPlausibleLoopSpeed {
+		warnf(messages.ValidateLoopSpeed, cfg.PenaltyLoopMaxSpeed, maxPlausibleLoopSpeed)
+	}
+}
+
+// competitorState представляет собой состояние участника при проверке последовательности событий.
+type competitorState struct {
+	drawn      bool
+	started    bool
+	onRange    bool
+	inPenalty  bool
+	finished   bool
+	withdrawn  bool
+	lapped     bool
+	dsq        bool
+	laps       int
+	rangeLap   int // Круг последнего посещения огневого рубежа
+	owed       int
+	targetsHit map[string]bool // Мишени, поражённые по событию 6 при текущем посещении огневого рубежа
+	shotHits   map[string]bool // Мишени, поражённые по событию 14 при текущем посещении огневого рубежа
+	shots      int             // Выстрелы по событию 14 при текущем посещении огневого рубежа
+}
+
+// eventValidator проверяет допустимость событий в текущем состоянии участников.
+type eventValidator struct {
+	report      *ValidationReport
+	config      *config.Config
+	origins     []string
+	known       *EventHandlerRegistry
+	competitors map[string]*competitorState
+	order       []string
+	skip        bool // Последовательность событий не проверяется
+}
+
+// validateEvents проверяет синтаксис, порядок времени и последовательность событий.
+// Известными считаются события, для которых в known назначены обработчики. Без загруженной
+// конфигурации (sequence = false) число кругов и огневых рубежей неизвестно, поэтому события
+// и состояние участников не проверяются: замечания о них были бы следствием ошибки в конфигурации.
+func validateEvents(report *ValidationReport, cfg *config.Config, known *EventHandlerRegistry, lines []eventLine, origins []string, sequence bool) error {
+	raceDay := cfg.Date.Day()
+	orderer, err := newEventOrderer(cfg, raceDay)
+	if err != nil {
+		return err
+	}
+	validator := &eventValidator{
+		report:      report,
+		config:      cfg,
+		origins:     origins,
+		known:       known,
+		competitors: make(map[string]*competitorState),
+		skip:        !sequence,
+	}
+
+	last := raceDay
+	for _, line := range lines {
+		if isSkippedEventLine(line.text) {
+			continue
+		}
+		event, err := parseEventLine(line.number, line.text, last)
+		if err != nil {
+			validator.lineIssue(SeverityError, CategorySyntax, err)
+			continue
+		}
+		if event.Time.After(last) {
+			last = event.Time
+		}
+		ready, err := orderer.push(&orderedEvent{eventLine: line, time: event.Time, event: event})
+		if err != nil {
+			validator.lineIssue(SeverityError, CategoryOrder, err)
+			continue
+		}
+		for _, ordered := range ready {
+			validator.check(ordered.event)
+		}
+	}
+	for _, ordered := range orderer.flush() {
+		validator.check(ordered.event)
+	}
+	for _, diagnostic := range orderer.diagnostics {
+		if origins != nil {
+			diagnostic.Origin = origins[diagnostic.Line-1]
+		}
+		report.add(SeverityWarning, CategoryOrder, FormatOrderDiagnostic(diagnostic, report.catalog))
+	}
+	validator.finish()
+
+	return nil
+}
+
+// lineIssue добавляет замечание по ошибке в строке события.
+func (v *eventValidator) lineIssue(severity, category string, err error) {
+	var lineErr *LineError
+	if !errors.As(err, &lineErr) {
+		v.report.add(severity, category, err.Error())
+		return
+	}
+	if v.origins != nil && lineErr.Line > 0 {
+		lineErr.Origin = v.origins[lineErr.Line-1]
+	}
+	v.report.add(severity, category, lineErr.describe())
+}
+
+// issue добавляет замечание к строке события event с описанием из каталога сообщений по ключу key.
+func (v *eventValidator) issue(event *Event, severity, category, key string, args ...any) {
+	message := v.report.catalog.Format(key, args...)
+	v.lineIssue(severity, category, &LineError{Line: event.line, Text: event.text, Err: errors.New(message)})
+}
+
+// check проверяет допустимость события в текущем состоянии участника и переводит участника в новое состояние.
+func (v *eventValidator) check(event *Event) {
+	if v.skip {
+		return
+	}
+	id := event.CompetitorID
+	competitor := v.competitors[id]
+	if event.ID == "1" {
+		if competitor != nil {
+			v.issue(event, SeverityError, CategoryCompetitors, messages.ValidateRegisteredTwice, id)
+			return
+		}
+		v.competitors[id] = &competitorState{}
+		v.order = append(v.order, id)
+		return
+	}
+	if _, ok := v.known.Handler(event.ID); !ok {
+		token := event.tokens[1]
+		v.lineIssue(SeverityWarning, CategorySyntax, event.lineError(token.column, "event ID "+describeEventIDs(v.known.EventIDs()), strconv.Quote(token.text)))
+		return
+	}
+	if competitor == nil {
+		v.issue(event, SeverityError, CategoryCompetitors, messages.ValidateNotRegistered, id)
+		return
+	}
+	if key := closedCompetitorKey(event.ID, competitor.finished, competitor.withdrawn); key != "" {
+		v.issue(event, SeverityError, CategorySequence, key, id)
+		return
+	}
+
+	switch event.ID {
+	case "2":
+		if err := event.RequireParams("start time"); err != nil {
+			v.lineIssue(SeverityError, CategorySyntax, err)
+			return
+		}
+		if _, err := clock.ParseTimestamp(event.Params[0], event.Time); err != nil {
+			v.lineIssue(SeverityError, CategorySyntax, event.InvalidParam(0, "start "+expectedTimestamp))
+			return
+		}
+		if competitor.started {
+			v.issue(event, SeverityError, CategorySequence, messages.ValidateDrawAfterStart, id)
+		} else if competitor.drawn {
+			v.issue(event, SeverityWarning, CategorySequence, messages.ValidateDrawnTwice, id)
+		}
+		competitor.drawn = true
+	case "3":
+		if !competitor.drawn {
+			v.issue(event, SeverityError, CategorySequence, messages.ValidateStartLineUndrawn, id)
+		} else if competitor.started {
+			v.issue(event, SeverityError, CategorySequence, messages.ValidateStartLineStarted, id)
+		}
+	case "4":
+		if !competitor.drawn {
+			v.issue(event, SeverityError, CategorySequence, messages.ValidateStartUndrawn, id)
+		} else if competitor.started {
+			v.issue(event, SeverityError, CategorySequence, messages.ValidateStartedTwice, id)
+		}
+		competitor.started = true
+	case "5":
+		if err := event.RequireParams("firing range"); err != nil {
+			v.lineIssue(SeverityError, CategorySyntax, err)
+			return
+		}
+		if firingRange, err := parseFiringRange(event, v.config); err != nil {
+			v.lineIssue(SeverityError, CategorySyntax, err)
+		} else if competitor.started {
+			v.checkCourseRange(event, competitor, firingRange)
+		}
+		switch {
+		case !competitor.started:
+			v.issue(event, SeverityError, CategorySequence, messages.ValidateRangeBeforeStart, id)
+		case competitor.onRange:
+			v.issue(event, SeverityError, CategorySequence, messages.ValidateRangeOnRange, id)
+		case competitor.inPenalty:
+			v.issue(event, SeverityError, CategorySequence, messages.ValidateRangeInPenalty, id)
+		}
+		competitor.onRange = true
+		competitor.targetsHit = make(map[string]bool)
+		competitor.shotHits = make(map[string]bool)
+		competitor.shots = 0
+	case "6":
+		if err := event.RequireParams("target"); err != nil {
+			v.lineIssue(SeverityError, CategorySyntax, err)
+			return
+		}
+		target, err := parseTarget(event)
+		if err != nil {
+			v.lineIssue(SeverityError, CategorySyntax, err)
+			return
+		}
+		if !competitor.onRange {
+			v.issue(event, SeverityError, CategorySequence, messages.ValidateHitOffRange, id)
+			return
+		}
+		if competitor.targetsHit[target] {
+			v.issue(event, SeverityError, CategorySequence, messages.ValidateHitTwice, id, target)
+			return
+		}
+		competitor.targetsHit[target] = true
+	case "7":
+		if !competitor.onRange {
+			v.issue(event, SeverityError, CategorySequence, messages.ValidateLeftRangeOffRange, id)
+			return
+		}
+		competitor.onRange = false
+		hits := len(competitor.targetsHit)
+		for target := range competitor.shotHits {
+			if !competitor.targetsHit[target] {
+				hits++
+			}
+		}
+		competitor.owed = targetsPerVisit - hits
+	case "8":
+		switch {
+		case IsPenaltyTimeMode(v.config):
+			v.issue(event, SeverityError, CategorySequence, messages.ValidatePenaltyTimeMode)
+		case competitor.onRange:
+			v.issue(event, SeverityError, CategorySequence, messages.ValidatePenaltyOnRange, id)
+		case competitor.inPenalty:
+			v.issue(event, SeverityError, CategorySequence, messages.ValidatePenaltyTwice, id)
+		case competitor.owed == 0:
+			v.issue(event, SeverityWarning, CategorySequence, messages.ValidatePenaltyWithoutMisses, id)
+		}
+		competitor.inPenalty = true
+	case "9":
+		if !competitor.inPenalty {
+			v.issue(event, SeverityError, CategorySequence, messages.ValidateLeftPenaltyOutside, id)
+			return
+		}
+		competitor.inPenalty = false
+		competitor.owed = 0
+	case "10":
+		switch {
+		case !competitor.started:
+			v.issue(event, SeverityError, CategorySequence, messages.ValidateLapBeforeStart, id)
+			return
+		case competitor.onRange:
+			v.issue(event, SeverityError, CategorySequence, messages.ValidateLapOnRange, id)
+		case competitor.inPenalty:
+			v.issue(event, SeverityError, CategorySequence, messages.ValidateLapInPenalty, id)
+		}
+		lap := competitor.laps + 1
+		if firingLine, ok := v.config.FiringLineOnLap(lap); ok && firingLine > 0 && competitor.rangeLap != lap {
+			v.issue(event, SeverityWarning, CategorySequence, messages.ValidateLapWithoutShooting, id, lap, firingLine)
+		}
+		competitor.laps++
+		competitor.finished = competitor.laps >= v.config.Laps
+	case "11":
+		competitor.withdrawn = true
+	case "12":
+		competitor.lapped = true
+	case "13":
+		if err := event.RequireParams("rule"); err != nil {
+			v.lineIssue(SeverityError, CategorySyntax, err)
+			return
+		}
+		competitor.dsq = true
+	case "14":
+		v.checkShot(event, competitor)
+	}
+}
+
+// checkShot проверяет выстрел по мишени (событие 14). Попадание по событию 14 может дублироваться
+// событием 6 для той же мишени, но не повторным событием 14.
+func (v *eventValidator) checkShot(event *Event, competitor *competitorState) {
+	id := event.CompetitorID
+	if err := event.RequireParams("target", "shot result"); err != nil {
+		v.lineIssue(SeverityError, CategorySyntax, err)
+		return
+	}
+	target, err := parseTarget(event)
+	if err != nil {
+		v.lineIssue(SeverityError, CategorySyntax, err)
+		return
+	}
+	hit, err := parseShotResult(event.Params[1])
+	if err != nil {
+		v.lineIssue(SeverityError, CategorySyntax, event.InvalidParam(1, "shot result "+ShotHit+" or "+ShotMiss))
+		return
+	}
+	if !competitor.onRange {
+		v.issue(event, SeverityError, CategorySequence, messages.ValidateShotOffRange, id)
+		return
+	}
+	competitor.shots++
+	if competitor.shots == targetsPerVisit+1 {
+		v.issue(event, SeverityWarning, CategorySequence, messages.ValidateTooManyShots, id, targetsPerVisit)
+	}
+	if !hit {
+		return
+	}
+	if competitor.shotHits[target] {
+		v.issue(event, SeverityError, CategorySequence, messages.ValidateHitTwice, id, target)
+		return
+	}
+	competitor.shotHits[target] = true
+}
+
+// describeEventIDs возвращает описание идентификаторов событий eventIDs, объединяя подряд идущие
+// номера в диапазоны, например, "1-14, 20".
+func describeEventIDs(eventIDs []string) string {
+	var parts []string
+	for i := 0; i < len(eventIDs); {
+		j := i
+		for j+1 < len(eventIDs) && isNextEventID(eventIDs[j], eventIDs[j+1]) {
+			j++
+		}
+		if j == i {
+			parts = append(parts, eventIDs[i])
+		} else {
+			parts = append(parts, eventIDs[i]+"-"+eventIDs[j])
+		}
+		i = j + 1
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+// isNextEventID сообщает, следует ли числовой идентификатор события next сразу за числовым идентификатором id.
+func isNextEventID(id, next string) bool {
+	a, errA := strconv.Atoi(id)
+	b, errB := strconv.Atoi(next)
+	return errA == nil && errB == nil && b == a+1
+}
+
+// checkCourseRange сверяет посещение огневого рубежа firingRange с огневым рубежом текущего круга
+// по описанию трассы. Без описания трассы посещение не проверяется.
+func (v *eventValidator) checkCourseRange(event *Event, competitor *competitorState, firingRange int) {
+	lap := competitor.laps + 1
+	expected, ok := v.config.FiringLineOnLap(lap)
+	switch {
+	case !ok:
+		return
+	case competitor.rangeLap == lap:
+		v.issue(event, SeverityError, CategorySequence, messages.ValidateRangeTwiceOnLap, event.CompetitorID, lap)
+	case expected == 0:
+		v.issue(event, SeverityError, CategorySequence, messages.ValidateRangeOffCourse, event.CompetitorID, lap)
+	case firingRange != expected:
+		v.issue(event, SeverityError, CategorySequence, messages.ValidateWrongFiringLine, event.CompetitorID, firingRange, lap, expected)
+	}
+	competitor.rangeLap = lap
+}
+
+// finish проверяет состояние участников после последнего события.
+func (v *eventValidator) finish() {
+	if v.skip {
+		return
+	}
+	for _, id := range v.order {
+		competitor := v.competitors[id]
+		switch {
+		case !competitor.drawn && !competitor.started:
+			v.report.addMessage(SeverityWarning, CategoryCompetitors, messages.ValidateNoStartTime, id)
+		case competitor.started && !competitor.finished && !competitor.withdrawn && !competitor.lapped && !competitor.dsq:
+			v.report.addMessage(SeverityWarning, CategoryCompetitors, messages.ValidateNotFinished, id)
+		}
+	}
+}
@@ -0,0 +1,203 @@
+package services_test
+
+import (
+	"system_prototype_for_biathlon_competitions/internal/entities"
+	"system_prototype_for_biathlon_competitions/internal/messages"
+	"system_prototype_for_biathlon_competitions/internal/services"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestValidate тестирует проверку конфигурационного файла и файла событий.
+func TestValidate(t *testing.T) {
+	t.Run("valid files", func(t *testing.T) {
+		configJSON := `{"laps": 1, "lapLen": 3500, "penaltyLen": 150, "firingLines": 1, "start": "09:30:00.000", "startDelta": "00:01:30"}`
+		events := "[09:00:00.000] 1 1\n" +
+			"[09:01:00.000] 2 1 09:30:00.000\n" +
+			"[09:30:01.000] 4 1\n" +
+			"[09:40:00.000] 5 1 1\n" +
+			"[09:40:10.000] 6 1 1\n" +
+			"[09:40:20.000] 7 1\n" +
+			"[09:41:00.000] 8 1\n" +
+			"[09:42:00.000] 9 1\n" +
+			"[09:50:00.000] 10 1\n"
+		service := services.NewParseService(&entities.Files{
+			ConfigFile: openTempFile(t, "config", configJSON),
+			EventsFile: openTempFile(t, "events", events),
+		})
+
+		report, err := service.Validate()
+		require.NoError(t, err)
+		require.False(t, report.HasErrors())
+		require.Equal(t, []string{"0 errors, 0 warnings"}, report.Lines())
+	})
+
+	t.Run("invalid files", func(t *testing.T) {
+		configJSON := `{"laps": 0, "lapLen": 50000, "penaltyLen": 150, "firingLines": 1, "start": "10:00", "startDelta": "00:01:30", "eventOrder": "warn"}`
+		events := "[09:00:00.000] 1 1\n" +
+			"[09:00:00.000] 1 1\n" +
+			"[09:00:30.000] 1 2\n" +
+			"[09:01:00.000] 2 1 10:00\n" +
+			"[09:01:00.000] 2 3 09:30:00.000\n" +
+			"[09:00:59.000] 4 1\n" +
+			"[09:40:00.000] 5 1 2\n" +
+			"[09:40:10.000] 6 1 1\n" +
+			"[09:40:20.000] 9 1\n" +
+			"[09:40:30.000] 20 1\n"
+		service := services.NewParseService(&entities.Files{
+			ConfigFile: openTempFile(t, "config", configJSON),
+			EventsFile: openTempFile(t, "events", events),
+		})
+
+		report, err := service.Validate()
+		require.NoError(t, err)
+		require.True(t, report.HasErrors())
+		require.Equal(t, []string{
+			"== Config ==",
+			`error: start: expected time HH:MM:SS.sss, found "10:00"`,
+			"error: laps: must be positive, found 0",
+			"warning: lapLen: 50000 m is outside the usual 500-10000 m",
+			"== Syntax ==",
+			`error: line 4, column 20: expected start time HH:MM:SS.sss or YYYY-MM-DDTHH:MM:SS.sss, found "10:00" in "[09:01:00.000] 2 1 10:00"`,
+			`error: line 7, column 20: expected firing range 1-1, found "2" in "[09:40:00.000] 5 1 2"`,
+			`warning: line 10, column 16: expected event ID 1-14, found "20" in "[09:40:30.000] 20 1"`,
+			"== Event order ==",
+			"warning: line 6: [09:00:59.000] 4 1 is 00:00:01.000 earlier than previous event, accepted",
+			"== Competitors ==",
+			`error: line 2: competitor 1 has already been registered in "[09:00:00.000] 1 1"`,
+			`error: line 5: competitor 3 is not registered in "[09:01:00.000] 2 3 09:30:00.000"`,
+			"warning: competitor 1 neither finished nor reported that they cannot continue",
+			"warning: competitor 2 has no start time",
+			"== Sequence ==",
+			`error: line 6: competitor 1 started without a start time in "[09:00:59.000] 4 1"`,
+			`error: line 9: competitor 1 left the penalty laps without entering them in "[09:40:20.000] 9 1"`,
+			"8 errors, 5 warnings",
+		}, report.Lines())
+	})
+
+	t.Run("unreadable config", func(t *testing.T) {
+		events := "[09:00:00.000] 1 1\n" +
+			"[09:30:01.000] 4 1\n" +
+			"[09:50:00.000] 10 1\n" +
+			"[09:55:00.000] 10 1\n"
+		service := services.NewParseService(&entities.Files{
+			ConfigFile: openTempFile(t, "config", `{"laps": 1,`),
+			EventsFile: openTempFile(t, "events", events),
+		})
+
+		report, err := service.Validate()
+		require.NoError(t, err)
+		require.True(t, report.HasErrors())
+		lines := report.Lines()
+		require.Len(t, lines, 3)
+		require.Equal(t, "== Config ==", lines[0])
+		require.Equal(t, "1 errors, 0 warnings", lines[2])
+	})
+
+	t.Run("implausible distance", func(t *testing.T) {
+		configJSON := `{"laps": 10, "lapLen": 4000, "penaltyLen": 150, "firingLines": 4, "start": "09:30:00.000", "startDelta": "00:01:30"}`
+		service := services.NewParseService(&entities.Files{
+			ConfigFile: openTempFile(t, "config", configJSON),
+			EventsFile: openTempFile(t, "events", ""),
+		})
+
+		report, err := service.Validate()
+		require.NoError(t, err)
+		require.Equal(t, []string{
+			"== Config ==",
+			"warning: distance: 40000 m over 10 laps is outside the usual 2000-30000 m",
+			"0 errors, 1 warnings",
+		}, report.Lines())
+	})
+
+	t.Run("russian locale", func(t *testing.T) {
+		configJSON := `{"laps": 10, "lapLen": 4000, "penaltyLen": 150, "firingLines": 4, "start": "09:30:00.000", "startDelta": "00:01:30"}`
+		service := services.NewParseService(&entities.Files{
+			ConfigFile: openTempFile(t, "config", configJSON),
+			EventsFile: openTempFile(t, "events", "[09:00:00.000] 1 1\n"),
+		})
+		catalog, err := messages.New("ru")
+		require.NoError(t, err)
+		service.SetMessages(catalog)
+
+		report, err := service.Validate()
+		require.NoError(t, err)
+		require.Equal(t, []string{
+			"== Конфигурация ==",
+			"предупреждение: distance: 40000 м за 10 кругов вне обычных 2000-30000 м",
+			"== Участники ==",
+			"предупреждение: у участника 1 нет времени старта",
+			"ошибок: 0, предупреждений: 2",
+		}, report.Lines())
+	})
+
+	t.Run("course", func(t *testing.T) {
+		configJSON := `{"course": [{"length": 2500, "firingLine": 1}, {"length": 3000, "firingLine": 2}, {"length": 3300}],
+			"penaltyLen": 150, "start": "09:30:00.000", "startDelta": "00:01:30"}`
+		events := "[09:00:00.000] 1 1\n" +
+			"[09:01:00.000] 2 1 09:30:00.000\n" +
+			"[09:30:01.000] 4 1\n" +
+			"[09:40:00.000] 5 1 2\n" +
+			"[09:40:20.000] 7 1\n" +
+			"[09:50:00.000] 10 1\n" +
+			"[10:00:00.000] 10 1\n" +
+			"[10:05:00.000] 5 1 1\n" +
+			"[10:05:20.000] 7 1\n" +
+			"[10:10:00.000] 10 1\n"
+		service := services.NewParseService(&entities.Files{
+			ConfigFile: openTempFile(t, "config", configJSON),
+			EventsFile: openTempFile(t, "events", events),
+		})
+
+		report, err := service.Validate()
+		require.NoError(t, err)
+		require.Equal(t, []string{
+			"== Sequence ==",
+			`error: line 4: competitor 1 is on firing range 2 on lap 1, expected firing line 1 by the course in "[09:40:00.000] 5 1 2"`,
+			`warning: line 7: competitor 1 ended lap 2 without shooting on firing line 2 in "[10:00:00.000] 10 1"`,
+			`error: line 8: competitor 1 is on the firing range on lap 3 without shooting by the course in "[10:05:00.000] 5 1 1"`,
+			"2 errors, 1 warnings",
+		}, report.Lines())
+	})
+
+	t.Run("shots", func(t *testing.T) {
+		configJSON := `{"laps": 1, "lapLen": 3000, "penaltyLen": 150, "firingLines": 1, "start": "09:30:00.000", "startDelta": "00:01:30"}`
+		events := "[09:00:00.000] 1 1\n" +
+			"[09:01:00.000] 2 1 09:30:00.000\n" +
+			"[09:30:01.000] 4 1\n" +
+			"[09:35:00.000] 14 1 1 hit\n" +
+			"[09:40:00.000] 5 1 1\n" +
+			"[09:40:01.000] 14 1 1 hit\n" +
+			"[09:40:01.000] 6 1 1\n" +
+			"[09:40:02.000] 14 1 1 hit\n" +
+			"[09:40:03.000] 14 1 6 miss\n" +
+			"[09:40:04.000] 14 1 2 missed\n" +
+			"[09:40:05.000] 14 1 2 miss\n" +
+			"[09:40:06.000] 14 1 3 miss\n" +
+			"[09:40:07.000] 14 1 4 miss\n" +
+			"[09:40:08.000] 14 1 5 hit\n" +
+			"[09:40:09.000] 14 1 5 miss\n" +
+			"[09:40:20.000] 7 1\n" +
+			"[09:41:00.000] 8 1\n" +
+			"[09:43:00.000] 9 1\n" +
+			"[10:00:00.000] 10 1\n"
+		service := services.NewParseService(&entities.Files{
+			ConfigFile: openTempFile(t, "config", configJSON),
+			EventsFile: openTempFile(t, "events", events),
+		})
+
+		report, err := service.Validate()
+		require.NoError(t, err)
+		require.Equal(t, []string{
+			"== Syntax ==",
+			`error: line 9, column 21: expected target 1-5, found "6" in "[09:40:03.000] 14 1 6 miss"`,
+			`error: line 10, column 23: expected shot result hit or miss, found "missed" in "[09:40:04.000] 14 1 2 missed"`,
+			"== Sequence ==",
+			`error: line 4: competitor 1 fired a shot outside the firing range in "[09:35:00.000] 14 1 1 hit"`,
+			`error: line 8: competitor 1 hit target 1 twice in "[09:40:02.000] 14 1 1 hit"`,
+			`warning: line 14: competitor 1 fired more than 5 shots on the firing range in "[09:40:08.000] 14 1 5 hit"`,
+			"4 errors, 1 warnings",
+		}, report.Lines())
+	})
+}
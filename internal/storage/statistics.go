@@ -0,0 +1,38 @@
+package storage
+
+import (
+	"maps"
+	"slices"
+	"strings"
+	"system_prototype_for_biathlon_competitions/internal/entities"
+)
+
+// StatisticRepository описывает хранилище статистики участников обрабатываемой гонки.
+// Сервисы разбора и отчётов читают и изменяют статистику только через него; в хранилище гонок
+// статистика попадает вместе с гонкой после её обработки.
+type StatisticRepository interface {
+	GetStatistic(competitorID string) *entities.Statistic
+	SaveStatistic(statistic *entities.Statistic)
+	ListStatistics() []*entities.Statistic
+}
+
+// MemoryStatistics представляет встроенное хранилище статистики участников в памяти,
+// ключом которого является идентификатор участника.
+type MemoryStatistics map[string]*entities.Statistic
+
+// GetStatistic возвращает статистику участника или nil, если участник не зарегистрирован.
+func (s MemoryStatistics) GetStatistic(competitorID string) *entities.Statistic {
+	return s[competitorID]
+}
+
+// SaveStatistic сохраняет статистику участника, заменяя ранее сохранённую.
+func (s MemoryStatistics) SaveStatistic(statistic *entities.Statistic) {
+	s[statistic.CompetitorID] = statistic
+}
+
+// ListStatistics возвращает статистику всех участников, упорядоченную по идентификатору участника.
+func (s MemoryStatistics) ListStatistics() []*entities.Statistic {
+	return slices.SortedFunc(maps.Values(s), func(a, b *entities.Statistic) int {
+		return strings.Compare(a.CompetitorID, b.CompetitorID)
+	})
+}
@@ -0,0 +1,117 @@
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"system_prototype_for_biathlon_competitions/internal/entities"
+)
+
+// ErrRaceNotFound возвращается, если гонка с указанным идентификатором отсутствует в хранилище.
+var ErrRaceNotFound = errors.New("race not found")
+
+// Repository описывает хранилище проведённых гонок. Гонка сохраняется и читается целиком
+// вместе со статистикой участников (см. StatisticRepository).
+type Repository interface {
+	SaveRace(race *entities.Race) error
+	GetRace(id string) (*entities.Race, error)
+	ListRaces() ([]*entities.Race, error)
+}
+
+// FileStorage представляет встроенное файловое хранилище гонок,
+// в котором каждая гонка хранится в отдельном JSON-файле каталога.
+type FileStorage struct {
+	dir string
+}
+
+// NewFileStorage создаёт файловое хранилище в каталоге dir, создавая каталог при необходимости.
+func NewFileStorage(dir string) (*FileStorage, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create storage directory: %w", err)
+	}
+
+	return &FileStorage{dir: dir}, nil
+}
+
+// SaveRace сохраняет гонку, перезаписывая ранее сохранённую гонку с тем же идентификатором.
+func (s *FileStorage) SaveRace(race *entities.Race) error {
+	path, err := s.racePath(race.ID)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(race, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode race: %w", err)
+	}
+
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write race file: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("failed to replace race file: %w", err)
+	}
+
+	return nil
+}
+
+// GetRace возвращает гонку по её идентификатору.
+func (s *FileStorage) GetRace(id string) (*entities.Race, error) {
+	path, err := s.racePath(id)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("%w: %s", ErrRaceNotFound, id)
+		}
+		return nil, fmt.Errorf("failed to read race file: %w", err)
+	}
+
+	race := &entities.Race{}
+	if err := json.Unmarshal(data, race); err != nil {
+		return nil, fmt.Errorf("failed to decode race: %w", err)
+	}
+
+	return race, nil
+}
+
+// ListRaces возвращает все сохранённые гонки, упорядоченные по времени сохранения.
+func (s *FileStorage) ListRaces() ([]*entities.Race, error) {
+	paths, err := filepath.Glob(filepath.Join(s.dir, "*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list race files: %w", err)
+	}
+
+	races := make([]*entities.Race, 0, len(paths))
+	for _, path := range paths {
+		id := strings.TrimSuffix(filepath.Base(path), ".json")
+		race, err := s.GetRace(id)
+		if err != nil {
+			return nil, err
+		}
+		races = append(races, race)
+	}
+
+	slices.SortFunc(races, func(a, b *entities.Race) int {
+		return a.CreatedAt.Compare(b.CreatedAt)
+	})
+
+	return races, nil
+}
+
+// racePath возвращает путь к файлу гонки, проверяя корректность идентификатора.
+func (s *FileStorage) racePath(id string) (string, error) {
+	if id == "" || strings.ContainsAny(id, `/\`) || id == "." || id == ".." {
+		return "", fmt.Errorf("invalid race id: %q", id)
+	}
+
+	return filepath.Join(s.dir, id+".json"), nil
+}
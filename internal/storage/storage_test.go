@@ -0,0 +1,84 @@
+package storage_test
+
+import (
+	"system_prototype_for_biathlon_competitions/internal/config"
+	"system_prototype_for_biathlon_competitions/internal/entities"
+	"system_prototype_for_biathlon_competitions/internal/storage"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestFileStorage тестирует сохранение и чтение гонок в файловом хранилище.
+func TestFileStorage(t *testing.T) {
+	t.Run("save and get race", func(t *testing.T) {
+		fileStorage, err := storage.NewFileStorage(t.TempDir())
+		require.NoError(t, err)
+
+		race := &entities.Race{
+			ID:          "sprint",
+			CreatedAt:   time.Date(2023, 10, 1, 12, 0, 0, 0, time.UTC),
+			Config:      &config.Config{Laps: 2, LapLen: 3500},
+			Competitors: []string{"1"},
+			Events:      []string{"[09:05:59.867] 1 1"},
+			Statistics: map[string]*entities.Statistic{
+				"1": {CompetitorID: "1", NumberOfHits: 4},
+			},
+			Results: []string{"[NotFinished] 1 [{,}, {,}] {,} 4/5"},
+		}
+		require.NoError(t, fileStorage.SaveRace(race))
+
+		saved, err := fileStorage.GetRace("sprint")
+		require.NoError(t, err)
+		require.Equal(t, race, saved)
+	})
+
+	t.Run("race not found", func(t *testing.T) {
+		fileStorage, err := storage.NewFileStorage(t.TempDir())
+		require.NoError(t, err)
+
+		_, err = fileStorage.GetRace("missing")
+		require.ErrorIs(t, err, storage.ErrRaceNotFound)
+	})
+
+	t.Run("invalid race id", func(t *testing.T) {
+		fileStorage, err := storage.NewFileStorage(t.TempDir())
+		require.NoError(t, err)
+
+		err = fileStorage.SaveRace(&entities.Race{ID: "../escape"})
+		require.Error(t, err)
+	})
+
+	t.Run("list races ordered by creation time", func(t *testing.T) {
+		fileStorage, err := storage.NewFileStorage(t.TempDir())
+		require.NoError(t, err)
+
+		require.NoError(t, fileStorage.SaveRace(&entities.Race{ID: "b", CreatedAt: time.Date(2023, 10, 2, 0, 0, 0, 0, time.UTC)}))
+		require.NoError(t, fileStorage.SaveRace(&entities.Race{ID: "a", CreatedAt: time.Date(2023, 10, 3, 0, 0, 0, 0, time.UTC)}))
+		require.NoError(t, fileStorage.SaveRace(&entities.Race{ID: "c", CreatedAt: time.Date(2023, 10, 1, 0, 0, 0, 0, time.UTC)}))
+
+		races, err := fileStorage.ListRaces()
+		require.NoError(t, err)
+		require.Len(t, races, 3)
+		require.Equal(t, "c", races[0].ID)
+		require.Equal(t, "b", races[1].ID)
+		require.Equal(t, "a", races[2].ID)
+	})
+}
+
+// TestMemoryStatistics тестирует хранилище статистики участников в памяти.
+func TestMemoryStatistics(t *testing.T) {
+	statistics := storage.MemoryStatistics{}
+	require.Nil(t, statistics.GetStatistic("1"))
+
+	statistics.SaveStatistic(&entities.Statistic{CompetitorID: "2"})
+	statistics.SaveStatistic(&entities.Statistic{CompetitorID: "1", NumberOfHits: 3})
+	statistics.SaveStatistic(&entities.Statistic{CompetitorID: "1", NumberOfHits: 4})
+
+	require.Equal(t, 4, statistics.GetStatistic("1").NumberOfHits)
+	require.Equal(t, []*entities.Statistic{
+		{CompetitorID: "1", NumberOfHits: 4},
+		{CompetitorID: "2"},
+	}, statistics.ListStatistics())
+}